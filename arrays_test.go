@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"language/backend"
+	"strings"
+	"testing"
+)
+
+// Arrays live in the stack frame of the function that created them, so
+// nothing holding one can be returned from it.
+func TestEscapingArrays(t *testing.T) {
+	programs := []string{
+		"mk = fn (x) [1, 2, x]\na = mk(3)\na[1]",
+		"mk = fn (x) {\n    a = [1, x]\n    fn (y) a[y]\n}\nmk(2)(1)",
+		"mk = fn (x: int) {\n    a = [x]\n    (x, (if (x < 2) a))\n}\nmk(0)",
+		"mk = fn (x: int) {\n    a = [x]\n    f = fn (i: int) -> int a[i]\n    g = fn (i: int) -> int i\n    if (x < 1) f else g\n}\nmk(0)(0)",
+	}
+	for _, source := range programs {
+		comp, errs := compile("", source)
+		if comp != nil || !strings.Contains(fmt.Sprint(errs), "from a function that creates arrays") {
+			t.Errorf("expected the array to be rejected, got %v\n%s", errs, source)
+		}
+	}
+}
+
+// An array passed to a function shares its items with the caller, and can be
+// returned to it since the caller's frame holds the storage.
+func TestArrayArguments(t *testing.T) {
+	source := `swap = fn (a) {
+    t = a[0]
+    a[0] = a[1]
+    a[1] = t
+    a
+}
+first = fn (a: int, b) b[a]
+xs = [1, 2]
+ys = swap(xs)
+ys[1] = ys[1] + 10
+print(xs)
+print(swap(swap(ys)))
+first(1, xs)`
+	comp, errs := compile("", source)
+	if comp == nil {
+		t.Fatalf("compilation failed: %v", errs)
+	}
+	vm := backend.NewVirtualMachine(comp.entry)
+	vm.MaxSteps = 1000000
+	output := &bytes.Buffer{}
+	vm.Output = output
+	result, err := vm.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[2, 11]\n[2, 11]\n11\n"; output.String() != want || result != 11 {
+		t.Errorf("expected output %q and result 11, got %q and %d", want, output.String(), result)
+	}
+}
@@ -3,7 +3,13 @@ package backend
 import "fmt"
 
 func NewProgram() *Program {
-	return &Program{[]*Value{}, []*Block{}}
+	return &Program{[]*Value{}, []*Block{}, []*Data{}}
+}
+
+func (program *Program) NewData(bytes []byte) *Data {
+	data := &Data{bytes, fmt.Sprintf("data%d", len(program.data))}
+	program.data = append(program.data, data)
+	return data
 }
 
 func (program *Program) NewBlock() *Block {
@@ -27,27 +33,19 @@ func (program *Program) NewValue() *Value {
 		interfere: map[*Value]struct{}{},
 		register:  "",
 		alive:     false,
-		value:     0,
 	}
 	program.values = append(program.values, val)
 	return val
 }
 
 func (block *Block) Binary(a, b *Value, op BinaryOp) *Value {
-	dest := block.program.NewValue()
+	return block.SizedBinary(a, b, op, WordSize, true)
+}
 
-	if len(a.defs) == 1 && len(b.defs) == 1 {
-		a, aok := a.defs[0].(*Constant)
-		b, bok := b.defs[0].(*Constant)
-		if aok && bok {
-			inst := &Constant{dest, a.val + b.val}
-			dest.defs = append(dest.defs, inst)
-			block.instructions = append(block.instructions, inst)
-		}
-		return dest
-	}
+func (block *Block) SizedBinary(a, b *Value, op BinaryOp, width int, signed bool) *Value {
+	dest := block.program.NewValue()
 
-	inst := &Binary{a, b, dest, op}
+	inst := &Binary{a, b, dest, op, width, signed}
 	block.instructions = append(block.instructions, inst)
 	dest.defs = append(dest.defs, inst)
 	a.uses = append(a.uses, inst)
@@ -70,6 +68,135 @@ func (block *Block) Copy(src, dest *Value) {
 	dest.defs = append(dest.defs, inst)
 }
 
+func (block *Block) WriteText(text string) {
+	if len(block.instructions) > 0 {
+		if last, ok := block.instructions[len(block.instructions)-1].(*WriteText); ok {
+			last.text += text
+			return
+		}
+	}
+	block.instructions = append(block.instructions, &WriteText{text})
+}
+
+func (block *Block) WriteInt(val *Value) {
+	inst := &WriteInt{val, false}
+	block.instructions = append(block.instructions, inst)
+	val.uses = append(val.uses, inst)
+}
+
+func (block *Block) WriteUint(val *Value) {
+	inst := &WriteInt{val, true}
+	block.instructions = append(block.instructions, inst)
+	val.uses = append(val.uses, inst)
+}
+
+func (block *Block) Extend(src *Value, width int, signed bool) *Value {
+	dest := block.program.NewValue()
+	inst := &Extend{src, dest, width, signed}
+	block.instructions = append(block.instructions, inst)
+	dest.defs = append(dest.defs, inst)
+	src.uses = append(src.uses, inst)
+	return dest
+}
+
+func (block *Block) WriteChar(val *Value) {
+	inst := &WriteChar{val}
+	block.instructions = append(block.instructions, inst)
+	val.uses = append(val.uses, inst)
+}
+
+func (block *Block) Address(data *Data) *Value {
+	dest := block.program.NewValue()
+	inst := &Address{dest, data}
+	block.instructions = append(block.instructions, inst)
+	dest.defs = append(dest.defs, inst)
+	return dest
+}
+
+func (block *Block) CodeAddress(function *Block) *Value {
+	dest := block.program.NewValue()
+	inst := &CodeAddress{dest, function}
+	block.instructions = append(block.instructions, inst)
+	dest.defs = append(dest.defs, inst)
+	return dest
+}
+
+func (block *Block) Write(addr, length *Value) {
+	inst := &Write{addr, length}
+	block.instructions = append(block.instructions, inst)
+	addr.uses = append(addr.uses, inst)
+	length.uses = append(length.uses, inst)
+}
+
+func (block *Block) Allocate(size int) *Value {
+	dest := block.program.NewValue()
+	inst := &Allocate{dest, size}
+	block.instructions = append(block.instructions, inst)
+	dest.defs = append(dest.defs, inst)
+	return dest
+}
+
+func (block *Block) Alloca(size int) *Value {
+	dest := block.program.NewValue()
+	inst := &Alloca{dest, size}
+	block.instructions = append(block.instructions, inst)
+	dest.defs = append(dest.defs, inst)
+	return dest
+}
+
+// MoveToHeap replaces the stack storage that block allocates for addr with
+// heap storage of the same size, for storage found to outlive its frame
+// after it was allocated.
+func (block *Block) MoveToHeap(addr *Value) {
+	for i, inst := range block.instructions {
+		alloca, ok := inst.(*Alloca)
+		if !ok || alloca.dest != addr {
+			continue
+		}
+		allocate := &Allocate{addr, alloca.size}
+		block.instructions[i] = allocate
+		for j, def := range addr.defs {
+			if def == inst {
+				addr.defs[j] = allocate
+			}
+		}
+	}
+}
+
+func (block *Block) Load(addr, index *Value, width int) *Value {
+	return block.load(addr, index, width, false)
+}
+
+func (block *Block) VolatileLoad(addr, index *Value, width int) *Value {
+	return block.load(addr, index, width, true)
+}
+
+func (block *Block) load(addr, index *Value, width int, volatile bool) *Value {
+	dest := block.program.NewValue()
+	inst := &Load{dest, addr, index, width, volatile}
+	block.instructions = append(block.instructions, inst)
+	dest.defs = append(dest.defs, inst)
+	addr.uses = append(addr.uses, inst)
+	index.uses = append(index.uses, inst)
+	return dest
+}
+
+func (block *Block) Store(addr, index, val *Value, width int) {
+	block.store(addr, index, val, width, false)
+}
+
+func (block *Block) VolatileStore(addr, index, val *Value, width int) {
+	block.store(addr, index, val, width, true)
+}
+
+func (block *Block) store(addr, index, val *Value, width int, volatile bool) {
+	inst := &Store{addr, index, val, width, volatile}
+	block.instructions = append(block.instructions, inst)
+	addr.uses = append(addr.uses, inst)
+	index.uses = append(index.uses, inst)
+	val.uses = append(val.uses, inst)
+}
+
 func (block *Block) Jump(target *Block) {
 	target.previousBlocks = append(target.previousBlocks, block)
 	block.branch = &Jump{target}
@@ -92,6 +219,10 @@ func (block *Block) JumpIfGreater(a, b *Value, ifTrue, ifFalse *Block) {
 	block.ConditionalJump(a, b, ifTrue, ifFalse, Greater)
 }
 
+func (block *Block) JumpIfAbove(a, b *Value, ifTrue, ifFalse *Block) {
+	block.ConditionalJump(a, b, ifTrue, ifFalse, Above)
+}
+
 func (block *Block) JumpIfLessOrEqual(a, b *Value, ifTrue, ifFalse *Block) {
 	block.ConditionalJump(a, b, ifTrue, ifFalse, LessOrEqual)
 }
@@ -106,12 +237,44 @@ func (block *Block) Exit(val *Value) {
 	block.branch = branch
 }
 
-func (block *Block) Call(target, ret *Block) {
+func (block *Block) Call(target, ret *Block, returns ...*Value) {
+	target.previousBlocks = append(target.previousBlocks, block)
+	ret.previousBlocks = append(ret.previousBlocks, block)
+	branch := &Call{target, ret, nil, nil, returns, map[*Value]struct{}{}}
+	for _, val := range returns {
+		val.defs = append(val.defs, branch)
+	}
+	block.branch = branch
+}
+
+// CallIndirect calls the function at the code address in fn. Every function
+// it may call must be added with AddCallTarget.
+func (block *Block) CallIndirect(fn *Value, ret *Block, returns ...*Value) {
+	ret.previousBlocks = append(ret.previousBlocks, block)
+	branch := &Call{nil, ret, fn, []*Block{}, returns, map[*Value]struct{}{}}
+	fn.uses = append(fn.uses, branch)
+	for _, val := range returns {
+		val.defs = append(val.defs, branch)
+	}
+	block.branch = branch
+}
+
+// AddCallTarget records that the indirect call ending block may run the
+// function starting at target.
+func (block *Block) AddCallTarget(target *Block) {
+	call := block.branch.(*Call)
 	target.previousBlocks = append(target.previousBlocks, block)
-	ret.previousBlocks = append(ret.previousBlocks, target)
-	block.branch = &Call{target, ret}
+	call.targets = append(call.targets, target)
+}
+
+func (block *Block) Return(vals ...*Value) {
+	branch := &Return{vals}
+	for _, val := range vals {
+		val.uses = append(val.uses, branch)
+	}
+	block.branch = branch
 }
 
-func (block *Block) Return() {
-	block.branch = &Return{}
+func (block *Block) Trap() {
+	block.branch = &Trap{}
 }
@@ -9,6 +9,22 @@ func CoalesceValues(before, after *Value) {
 			inst.dest = after
 		case *Copy:
 			inst.dest = after
+		case *Address:
+			inst.dest = after
+		case *CodeAddress:
+			inst.dest = after
+		case *Allocate:
+			inst.dest = after
+		case *Alloca:
+			inst.dest = after
+		case *Load:
+			inst.dest = after
+		case *Extend:
+			inst.dest = after
+		case *Call:
+			for i := range inst.returns {
+				inst.returns[i] = ReplaceValue(inst.returns[i], before, after)
+			}
 		}
 	}
 	for _, inst := range before.uses {
@@ -18,11 +34,33 @@ func CoalesceValues(before, after *Value) {
 			inst.b = ReplaceValue(inst.b, before, after)
 		case *Copy:
 			inst.src = ReplaceValue(inst.src, before, after)
+		case *WriteInt:
+			inst.val = ReplaceValue(inst.val, before, after)
+		case *WriteChar:
+			inst.val = ReplaceValue(inst.val, before, after)
+		case *Write:
+			inst.addr = ReplaceValue(inst.addr, before, after)
+			inst.length = ReplaceValue(inst.length, before, after)
+		case *Extend:
+			inst.src = ReplaceValue(inst.src, before, after)
+		case *Load:
+			inst.addr = ReplaceValue(inst.addr, before, after)
+			inst.index = ReplaceValue(inst.index, before, after)
+		case *Store:
+			inst.addr = ReplaceValue(inst.addr, before, after)
+			inst.index = ReplaceValue(inst.index, before, after)
+			inst.val = ReplaceValue(inst.val, before, after)
 		case *Exit:
 			inst.val = ReplaceValue(inst.val, before, after)
 		case *ConditionalJump:
 			inst.a = ReplaceValue(inst.a, before, after)
 			inst.b = ReplaceValue(inst.b, before, after)
+		case *Return:
+			for i := range inst.vals {
+				inst.vals[i] = ReplaceValue(inst.vals[i], before, after)
+			}
+		case *Call:
+			inst.fn = ReplaceValue(inst.fn, before, after)
 		}
 	}
 	for val := range before.interfere {
@@ -10,6 +10,11 @@ func MarkUsedValue(val *Value) {
 				MarkUsedValue(inst.b)
 			case *Copy:
 				MarkUsedValue(inst.src)
+			case *Extend:
+				MarkUsedValue(inst.src)
+			case *Load:
+				MarkUsedValue(inst.addr)
+				MarkUsedValue(inst.index)
 			}
 		}
 	}
@@ -17,12 +22,39 @@ func MarkUsedValue(val *Value) {
 
 func MarkUsedValues(program *Program) {
 	for _, block := range program.blocks {
+		for _, inst := range block.instructions {
+			switch inst := inst.(type) {
+			case *WriteInt:
+				MarkUsedValue(inst.val)
+			case *WriteChar:
+				MarkUsedValue(inst.val)
+			case *Write:
+				MarkUsedValue(inst.addr)
+				MarkUsedValue(inst.length)
+			case *Store:
+				MarkUsedValue(inst.addr)
+				MarkUsedValue(inst.index)
+				MarkUsedValue(inst.val)
+			case *Load:
+				if inst.volatile {
+					MarkUsedValue(inst.dest)
+				}
+			}
+		}
 		switch branch := block.branch.(type) {
 		case *Exit:
 			MarkUsedValue(branch.val)
 		case *ConditionalJump:
 			MarkUsedValue(branch.a)
 			MarkUsedValue(branch.b)
+		case *Return:
+			for _, val := range branch.vals {
+				MarkUsedValue(val)
+			}
+		case *Call:
+			if branch.fn != nil {
+				MarkUsedValue(branch.fn)
+			}
 		}
 	}
 }
@@ -44,6 +76,32 @@ func RemoveDeadCode(program *Program) {
 				if inst.dest.alive {
 					insts = append(insts, inst)
 				}
+			case *Address:
+				if inst.dest.alive {
+					insts = append(insts, inst)
+				}
+			case *CodeAddress:
+				if inst.dest.alive {
+					insts = append(insts, inst)
+				}
+			case *Extend:
+				if inst.dest.alive {
+					insts = append(insts, inst)
+				}
+			case *Allocate:
+				if inst.dest.alive {
+					insts = append(insts, inst)
+				}
+			case *Alloca:
+				if inst.dest.alive {
+					insts = append(insts, inst)
+				}
+			case *Load:
+				if inst.dest.alive {
+					insts = append(insts, inst)
+				}
+			default:
+				insts = append(insts, inst)
 			}
 		}
 		block.instructions = insts
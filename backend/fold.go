@@ -0,0 +1,45 @@
+package backend
+
+// constantValue returns the value of val when its only definition is a
+// constant.
+func constantValue(val *Value) (int, bool) {
+	if len(val.defs) != 1 {
+		return 0, false
+	}
+	inst, ok := val.defs[0].(*Constant)
+	if !ok {
+		return 0, false
+	}
+	return inst.val, true
+}
+
+// FoldConstants replaces every binary instruction whose operands are both
+// constants with the constant it computes. It runs on the finished program
+// because a value defined by a single constant while a block is built may be
+// copied into later, as loop variables are at the end of each iteration.
+func FoldConstants(program *Program) {
+	for changed := true; changed; {
+		changed = false
+		for _, block := range program.blocks {
+			for i, inst := range block.instructions {
+				binary, ok := inst.(*Binary)
+				if !ok {
+					continue
+				}
+				a, aok := constantValue(binary.a)
+				b, bok := constantValue(binary.b)
+				if !aok || !bok {
+					continue
+				}
+				folded := &Constant{binary.dest, wrap(a+b, binary.width, binary.signed)}
+				block.instructions[i] = folded
+				for j, def := range binary.dest.defs {
+					if def == inst {
+						binary.dest.defs[j] = folded
+					}
+				}
+				changed = true
+			}
+		}
+	}
+}
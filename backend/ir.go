@@ -1,6 +1,9 @@
 package backend
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Value struct {
 	defs      []Instruction
@@ -9,7 +12,6 @@ type Value struct {
 	interfere map[*Value]struct{}
 	register  string
 	alive     bool
-	value     int
 }
 
 func (value Value) String() string {
@@ -22,9 +24,19 @@ const (
 	Add BinaryOp = "add"
 )
 
+// Binary wraps its result to width bytes, sign extending it when signed.
 type Binary struct {
 	a, b, dest *Value
 	op         BinaryOp
+	width      int
+	signed     bool
+}
+
+// Extend wraps src to width bytes, sign or zero extending it into dest.
+type Extend struct {
+	src, dest *Value
+	width     int
+	signed    bool
 }
 
 type Constant struct {
@@ -36,6 +48,68 @@ type Copy struct {
 	src, dest *Value
 }
 
+type WriteText struct {
+	text string
+}
+
+type WriteInt struct {
+	val      *Value
+	unsigned bool
+}
+
+type WriteChar struct {
+	val *Value
+}
+
+type Address struct {
+	dest *Value
+	data *Data
+}
+
+// CodeAddress is the address of the function starting at block, which can
+// be called indirectly.
+type CodeAddress struct {
+	dest  *Value
+	block *Block
+}
+
+type Write struct {
+	addr, length *Value
+}
+
+// Allocate reserves size bytes of heap storage, giving dest its address.
+// The heap is a fixed arena of HeapSize bytes that is never freed, and
+// running out of it traps.
+type Allocate struct {
+	dest *Value
+	size int
+}
+
+const HeapSize = 1 << 20
+
+// Alloca reserves size bytes of stack storage, giving dest its address.
+type Alloca struct {
+	dest *Value
+	size int
+}
+
+// Load and Store access the width bytes at addr + index * width. Loads zero
+// extend narrow values and stores truncate them. Volatile accesses reach
+// memory-mapped peripherals and are never removed.
+type Load struct {
+	dest, addr, index *Value
+	width             int
+	volatile          bool
+}
+
+type Store struct {
+	addr, index, val *Value
+	width            int
+	volatile         bool
+}
+
+const WordSize = 8
+
 type Instruction interface{}
 
 type Branch interface{}
@@ -43,6 +117,14 @@ type Branch interface{}
 type Program struct {
 	values []*Value
 	blocks []*Block
+	data   []*Data
+}
+
+// Data is a read-only byte sequence that lives in the data segment of the
+// VM and the .rodata section of native code.
+type Data struct {
+	bytes []byte
+	name  string
 }
 
 type Block struct {
@@ -68,6 +150,7 @@ const (
 	Equal       JumpCondition = "equal"
 	LessOrEqual JumpCondition = "lessOrEqual"
 	Greater     JumpCondition = "greater"
+	Above       JumpCondition = "above"
 )
 
 type ConditionalJump struct {
@@ -76,44 +159,148 @@ type ConditionalJump struct {
 	cond            JumpCondition
 }
 
+// Call runs the function starting at target and continues at ret, where the
+// function has defined returns. An indirect call instead runs the function
+// at the code address in fn, which is one of targets. Liveness analysis
+// collects the values that stay live across the call in saved, which are
+// preserved around it.
 type Call struct {
 	target, ret *Block
+	fn          *Value
+	targets     []*Block
+	returns     []*Value
+	saved       map[*Value]struct{}
+}
+
+// callees returns the functions that call may run.
+func (call *Call) callees() []*Block {
+	if call.fn != nil {
+		return call.targets
+	}
+	return []*Block{call.target}
+}
+
+type Return struct {
+	vals []*Value
 }
 
-type Return struct{}
+type Trap struct{}
 
 func IrToStr(program *Program) string {
 	str := ""
+	for _, data := range program.data {
+		str += fmt.Sprintf("%s = %q\n", data.name, data.bytes)
+	}
+	if len(program.data) > 0 {
+		str += "\n"
+	}
 	for _, block := range program.blocks {
 		str += fmt.Sprintf("%s {\n", block.name)
 		for _, inst := range block.instructions {
-			switch inst := inst.(type) {
-			case *Constant:
-				str += fmt.Sprintf("  %s = %d\n", inst.dest.name, inst.val)
-			case *Binary:
-				str += fmt.Sprintf("  %s = %s + %s\n", inst.dest.name, inst.a.name, inst.b.name)
-			case *Copy:
-				str += fmt.Sprintf("  %s = %s\n", inst.dest.name, inst.src.name)
-			}
+			str += fmt.Sprintf("  %s\n", InstToStr(inst))
 		}
-		switch branch := block.branch.(type) {
-		case *Jump:
-			str += fmt.Sprintf("  goto %s\n", branch.target.name)
-		case *ConditionalJump:
-			str += fmt.Sprintf("  goto %s if %s < %s else goto %s\n", branch.ifTrue.name, branch.a.name, branch.b.name, branch.ifFalse.name)
-		case *Exit:
-			str += fmt.Sprintf("  exit(%s)\n", branch.val)
-		case *Call:
-			str += fmt.Sprintf("  %s()\n", branch.target.name)
-			str += fmt.Sprintf("  goto %s\n", branch.ret.name)
-		case *Return:
-			str += fmt.Sprintf("  return\n")
+		if block.branch != nil {
+			str += fmt.Sprintf("  %s\n", InstToStr(block.branch))
 		}
 		str += "}\n\n"
 	}
 	return str
 }
 
+func InstToStr(inst interface{}) string {
+	switch inst := inst.(type) {
+	case *Constant:
+		return fmt.Sprintf("%s = %d", inst.dest.name, inst.val)
+	case *Binary:
+		if inst.width < WordSize {
+			return fmt.Sprintf("%s = %s + %s as %s", inst.dest.name, inst.a.name, inst.b.name, intName(inst.width, inst.signed))
+		}
+		return fmt.Sprintf("%s = %s + %s", inst.dest.name, inst.a.name, inst.b.name)
+	case *Extend:
+		return fmt.Sprintf("%s = %s(%s)", inst.dest.name, intName(inst.width, inst.signed), inst.src.name)
+	case *Copy:
+		return fmt.Sprintf("%s = %s", inst.dest.name, inst.src.name)
+	case *WriteText:
+		return fmt.Sprintf("write %q", inst.text)
+	case *WriteInt:
+		if inst.unsigned {
+			return fmt.Sprintf("write_uint(%s)", inst.val.name)
+		}
+		return fmt.Sprintf("write_int(%s)", inst.val.name)
+	case *WriteChar:
+		return fmt.Sprintf("write_char(%s)", inst.val.name)
+	case *Address:
+		return fmt.Sprintf("%s = &%s", inst.dest.name, inst.data.name)
+	case *Write:
+		return fmt.Sprintf("write(%s, %s)", inst.addr.name, inst.length.name)
+	case *CodeAddress:
+		return fmt.Sprintf("%s = &%s", inst.dest.name, inst.block.name)
+	case *Allocate:
+		return fmt.Sprintf("%s = allocate %d", inst.dest.name, inst.size)
+	case *Alloca:
+		return fmt.Sprintf("%s = alloca %d", inst.dest.name, inst.size)
+	case *Load:
+		str := fmt.Sprintf("%s = load%d(%s, %s)", inst.dest.name, inst.width, inst.addr.name, inst.index.name)
+		if inst.volatile {
+			str = fmt.Sprintf("%s = volatile load%d(%s, %s)", inst.dest.name, inst.width, inst.addr.name, inst.index.name)
+		}
+		return str
+	case *Store:
+		if inst.volatile {
+			return fmt.Sprintf("volatile store%d(%s, %s, %s)", inst.width, inst.addr.name, inst.index.name, inst.val.name)
+		}
+		return fmt.Sprintf("store%d(%s, %s, %s)", inst.width, inst.addr.name, inst.index.name, inst.val.name)
+	case *Jump:
+		return fmt.Sprintf("goto %s", inst.target.name)
+	case *ConditionalJump:
+		return fmt.Sprintf("goto %s if %s < %s else goto %s", inst.ifTrue.name, inst.a.name, inst.b.name, inst.ifFalse.name)
+	case *Exit:
+		return fmt.Sprintf("exit(%s)", inst.val)
+	case *Call:
+		var callee string
+		if inst.fn != nil {
+			callee = "*" + inst.fn.name
+		} else {
+			callee = inst.target.name
+		}
+		if len(inst.returns) > 0 {
+			return fmt.Sprintf("%s = %s()\n  goto %s", valueNames(inst.returns), callee, inst.ret.name)
+		}
+		return fmt.Sprintf("%s()\n  goto %s", callee, inst.ret.name)
+	case *Return:
+		if len(inst.vals) > 0 {
+			return "return " + valueNames(inst.vals)
+		}
+		return "return"
+	case *Trap:
+		return "trap"
+	}
+	return fmt.Sprintf("unknown %T", inst)
+}
+
+func valueNames(vals []*Value) string {
+	names := make([]string, len(vals))
+	for i, val := range vals {
+		names[i] = val.name
+	}
+	return strings.Join(names, ", ")
+}
+
+func intName(width int, signed bool) string {
+	if signed {
+		return fmt.Sprintf("i%d", width*8)
+	}
+	return fmt.Sprintf("u%d", width*8)
+}
+
+func (data *Data) Name() string {
+	return data.name
+}
+
 func (block *Block) SetName(name string) {
 	block.name = name
 }
+
+func (block *Block) Name() string {
+	return block.name
+}
@@ -3,14 +3,29 @@ package backend
 func LivenessAnalysis(exit *Block, program *Program) {
 	for _, block := range program.blocks {
 		block.liveOut = map[*Value]struct{}{}
+		if call, ok := block.branch.(*Call); ok {
+			call.saved = map[*Value]struct{}{}
+		}
 	}
 	livenessAnalysis(exit)
+	for _, block := range program.blocks {
+		livenessAnalysis(block)
+	}
 }
 
 func KillValue(liveIn map[*Value]struct{}, val *Value) {
 	delete(liveIn, val)
 }
 
+func DefineValue(liveIn map[*Value]struct{}, dest, src *Value) {
+	for val := range liveIn {
+		if val != dest && val != src {
+			InterfereValues(dest, val)
+		}
+	}
+	KillValue(liveIn, dest)
+}
+
 func ReviveValue(liveIn map[*Value]struct{}, value *Value) {
 	if _, prs := liveIn[value]; !prs {
 		for val := range liveIn {
@@ -39,28 +54,79 @@ func livenessAnalysis(block *Block) {
 
 	case *Exit:
 		ReviveValue(liveIn, branch.val)
+
+	case *Return:
+		for _, val := range branch.vals {
+			ReviveValue(liveIn, val)
+		}
+
+	case *Call:
+		if branch.fn != nil {
+			ReviveValue(liveIn, branch.fn)
+		}
 	}
 
 	for i := range block.instructions {
 		inst := block.instructions[len(block.instructions)-i-1]
 		switch inst := inst.(type) {
 		case *Constant:
-			KillValue(liveIn, inst.dest)
+			DefineValue(liveIn, inst.dest, nil)
 
 		case *Binary:
-			KillValue(liveIn, inst.dest)
+			DefineValue(liveIn, inst.dest, nil)
 			ReviveValue(liveIn, inst.a)
 			ReviveValue(liveIn, inst.b)
 
 		case *Copy:
-			KillValue(liveIn, inst.dest)
+			DefineValue(liveIn, inst.dest, inst.src)
+			ReviveValue(liveIn, inst.src)
+
+		case *WriteInt:
+			ReviveValue(liveIn, inst.val)
+
+		case *WriteChar:
+			ReviveValue(liveIn, inst.val)
+
+		case *Address:
+			DefineValue(liveIn, inst.dest, nil)
+
+		case *CodeAddress:
+			DefineValue(liveIn, inst.dest, nil)
+
+		case *Write:
+			ReviveValue(liveIn, inst.addr)
+			ReviveValue(liveIn, inst.length)
+
+		case *Extend:
+			DefineValue(liveIn, inst.dest, nil)
 			ReviveValue(liveIn, inst.src)
+
+		case *Allocate:
+			DefineValue(liveIn, inst.dest, nil)
+
+		case *Alloca:
+			DefineValue(liveIn, inst.dest, nil)
+
+		case *Load:
+			DefineValue(liveIn, inst.dest, nil)
+			ReviveValue(liveIn, inst.addr)
+			ReviveValue(liveIn, inst.index)
+
+		case *Store:
+			ReviveValue(liveIn, inst.addr)
+			ReviveValue(liveIn, inst.index)
+			ReviveValue(liveIn, inst.val)
 		}
 	}
 
 	for _, previous := range block.previousBlocks {
 		updated := false
 		for val := range liveIn {
+			if call, ok := previous.branch.(*Call); ok && call.ret == block {
+				if !saveAcrossCall(call, val) {
+					continue
+				}
+			}
 			if _, prs := previous.liveOut[val]; !prs {
 				previous.liveOut[val] = struct{}{}
 				updated = true
@@ -71,3 +137,19 @@ func livenessAnalysis(block *Block) {
 		}
 	}
 }
+
+// saveAcrossCall records that val is live when call returns, unless the call
+// defines it. Saved values are preserved around the call, so they must not
+// share a register with the values it returns.
+func saveAcrossCall(call *Call, val *Value) bool {
+	for _, ret := range call.returns {
+		if ret == val {
+			return false
+		}
+	}
+	call.saved[val] = struct{}{}
+	for _, ret := range call.returns {
+		InterfereValues(ret, val)
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package backend
+
+// Peripheral simulates a memory-mapped device register in the virtual
+// machine, receiving every volatile access to its address.
+type Peripheral interface {
+	Load(width int) int
+	Store(width, value int)
+}
+
+// Register is a peripheral that reads back the last value stored to it. The
+// virtual machine maps one to every address it has no peripheral for.
+type Register struct {
+	Value int
+}
+
+func (reg *Register) Load(width int) int {
+	return truncate(reg.Value, width)
+}
+
+func (reg *Register) Store(width, value int) {
+	reg.Value = truncate(value, width)
+}
+
+func (vm *VirtualMachine) peripheral(addr int) Peripheral {
+	if vm.Peripherals == nil {
+		vm.Peripherals = map[int]Peripheral{}
+	}
+	peripheral, ok := vm.Peripherals[addr]
+	if !ok {
+		peripheral = &Register{}
+		vm.Peripherals[addr] = peripheral
+	}
+	return peripheral
+}
@@ -1,10 +1,18 @@
 package backend
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+)
 
-func RegisterAllocation(program *Program) {
-	registers := []string{"%eax", "%ecx", "%edx", "%ebx", "%esp", "%ebp", "%esi", "%edi"}
+// registers are the registers values are allocated to, in order of
+// preference. %rsp and the frame pointer %rbp are never allocated.
+var registers = []string{
+	"%rax", "%rcx", "%rdx", "%rbx", "%rsi", "%rdi",
+	"%r8", "%r9", "%r10", "%r11", "%r12", "%r13", "%r14", "%r15",
+}
 
+func RegisterAllocation(program *Program) error {
 	stack := []*Value{}
 	values := make([]*Value, len(program.values))
 	copy(values, program.values)
@@ -38,8 +46,9 @@ func RegisterAllocation(program *Program) {
 			}
 		}
 		if val.register == "" {
-			panic("BAD")
+			return fmt.Errorf("too many live values to allocate a register for '%s'", val.name)
 		}
 		stack = stack[:len(stack)-1]
 	}
+	return nil
 }
@@ -1,56 +1,405 @@
 package backend
 
-import "fmt"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
 
-func Execute(block *Block) {
-	stack := []*Block{}
+var ErrStepLimit = errors.New("step limit exceeded")
+
+var ErrTrap = errors.New("trap")
+
+type BreakpointError struct {
+	Block *Block
+}
+
+func (err BreakpointError) Error() string {
+	return fmt.Sprintf("breakpoint at block '%s'", err.Block.name)
+}
+
+// dataStart is the address of the first byte of the VM data segment, which
+// keeps zero an invalid address.
+const dataStart = 4096
+
+// stackStart is the address of the bottom of the VM stack, which is kept apart
+// from the data segment.
+const stackStart = 1 << 24
+
+// heapStart is the address of the first byte of the VM heap, which lies above
+// the stack.
+const heapStart = 1 << 32
+
+// codeStart is the address of the first function whose address is taken,
+// which the VM numbers consecutively apart from data and stack addresses.
+const codeStart = 1 << 40
+
+// frame is the stack storage of one call, giving each Alloca its own slot
+// like the stack frame layout of native code, along with the values the call
+// has set. Values it reads without setting them come from its callers.
+type frame struct {
+	ret     *Block
+	base    int
+	allocas map[*Alloca]int
+	values  map[*Value]int
+}
+
+type VirtualMachine struct {
+	data        map[*Data]int
+	code        []*Block
+	memory      []byte
+	stack       []byte
+	heap        []byte
+	frame       frame
+	frames      []frame
+	block       *Block
+	resumed     bool
+	steps       int
+	breakpoints map[*Block]struct{}
+
+	MaxSteps    int
+	Trace       func(block *Block, inst interface{})
+	Output      io.Writer
+	Peripherals map[int]Peripheral
+}
+
+func NewVirtualMachine(entry *Block) *VirtualMachine {
+	return &VirtualMachine{
+		data:        map[*Data]int{},
+		frame:       frame{allocas: map[*Alloca]int{}, values: map[*Value]int{}},
+		frames:      []frame{},
+		block:       entry,
+		breakpoints: map[*Block]struct{}{},
+	}
+}
+
+// Start moves execution to block with an empty call stack, keeping the
+// values and outermost stack frame of earlier runs.
+func (vm *VirtualMachine) Start(block *Block) {
+	vm.block = block
+	for len(vm.frames) > 0 {
+		vm.stack = vm.stack[:vm.frame.base]
+		vm.frame = vm.frames[len(vm.frames)-1]
+		vm.frames = vm.frames[:len(vm.frames)-1]
+	}
+	vm.resumed = false
+	vm.steps = 0
+}
+
+func (vm *VirtualMachine) GetValue(val *Value) int {
+	return vm.get(val)
+}
+
+func (vm *VirtualMachine) SetValue(val *Value, value int) {
+	vm.frame.values[val] = value
+}
+
+// get looks val up in the current frame, and then in the frames of the
+// callers from the innermost out, as functions read values such as late
+// bound names from the frames that set them.
+func (vm *VirtualMachine) get(val *Value) int {
+	if value, ok := vm.frame.values[val]; ok {
+		return value
+	}
+	for i := len(vm.frames) - 1; i >= 0; i-- {
+		if value, ok := vm.frames[i].values[val]; ok {
+			return value
+		}
+	}
+	return 0
+}
+
+// ReadMemory returns length bytes of memory starting at addr.
+func (vm *VirtualMachine) ReadMemory(addr, length int) ([]byte, error) {
+	memory, offset := vm.memory, addr-dataStart
+	if addr >= heapStart {
+		memory, offset = vm.heap, addr-heapStart
+	} else if addr >= stackStart {
+		memory, offset = vm.stack, addr-stackStart
+	}
+	if offset < 0 || length < 0 || offset+length > len(memory) {
+		return nil, fmt.Errorf("invalid memory access of %d bytes at %d", length, addr)
+	}
+	return memory[offset : offset+length], nil
+}
+
+// address places data in the data segment the first time it is used.
+func (vm *VirtualMachine) address(data *Data) int {
+	addr, ok := vm.data[data]
+	if !ok {
+		addr = dataStart + len(vm.memory)
+		vm.memory = append(vm.memory, data.bytes...)
+		vm.data[data] = addr
+	}
+	return addr
+}
+
+// ReadWord returns the word at addr + index * WordSize.
+func (vm *VirtualMachine) ReadWord(addr, index int) (int, error) {
+	return vm.load(addr, index, WordSize)
+}
+
+func (vm *VirtualMachine) load(addr, index, width int) (int, error) {
+	bytes, err := vm.ReadMemory(addr+index*width, width)
+	if err != nil {
+		return 0, err
+	}
+	switch width {
+	case 1:
+		return int(bytes[0]), nil
+	case 2:
+		return int(binary.LittleEndian.Uint16(bytes)), nil
+	case 4:
+		return int(binary.LittleEndian.Uint32(bytes)), nil
+	case 8:
+		return int(int64(binary.LittleEndian.Uint64(bytes))), nil
+	}
+	return 0, fmt.Errorf("invalid access width %d", width)
+}
+
+func (vm *VirtualMachine) store(addr, index, width, value int) error {
+	bytes, err := vm.ReadMemory(addr+index*width, width)
+	if err != nil {
+		return err
+	}
+	switch width {
+	case 1:
+		bytes[0] = byte(value)
+	case 2:
+		binary.LittleEndian.PutUint16(bytes, uint16(value))
+	case 4:
+		binary.LittleEndian.PutUint32(bytes, uint32(value))
+	case 8:
+		binary.LittleEndian.PutUint64(bytes, uint64(value))
+	default:
+		return fmt.Errorf("invalid access width %d", width)
+	}
+	return nil
+}
+
+// codeAddress numbers block the first time its address is taken.
+func (vm *VirtualMachine) codeAddress(block *Block) int {
+	for i, function := range vm.code {
+		if function == block {
+			return codeStart + i
+		}
+	}
+	vm.code = append(vm.code, block)
+	return codeStart + len(vm.code) - 1
+}
+
+func (vm *VirtualMachine) alloca(inst *Alloca) int {
+	addr, ok := vm.frame.allocas[inst]
+	if !ok {
+		addr = stackStart + len(vm.stack)
+		vm.stack = append(vm.stack, make([]byte, inst.size)...)
+		vm.frame.allocas[inst] = addr
+	}
+	return addr
+}
+
+func (vm *VirtualMachine) Block() *Block {
+	return vm.block
+}
+
+func (vm *VirtualMachine) Steps() int {
+	return vm.steps
+}
+
+func (vm *VirtualMachine) SetBreakpoint(block *Block) {
+	vm.breakpoints[block] = struct{}{}
+}
+
+func (vm *VirtualMachine) ClearBreakpoint(block *Block) {
+	delete(vm.breakpoints, block)
+}
+
+func (vm *VirtualMachine) step(inst interface{}) error {
+	if vm.MaxSteps > 0 && vm.steps >= vm.MaxSteps {
+		return ErrStepLimit
+	}
+	vm.steps++
+	if vm.Trace != nil {
+		vm.Trace(vm.block, inst)
+	}
+	return nil
+}
+
+// Run executes from the current block until the program exits, an error
+// occurs or a breakpoint is reached. Calling Run again after a breakpoint
+// resumes execution from the start of that block.
+func (vm *VirtualMachine) Run() (int, error) {
 	for {
-		for _, inst := range block.instructions {
+		if _, ok := vm.breakpoints[vm.block]; ok && !vm.resumed {
+			vm.resumed = true
+			return 0, BreakpointError{vm.block}
+		}
+		vm.resumed = false
+
+		for _, inst := range vm.block.instructions {
+			if err := vm.step(inst); err != nil {
+				return 0, err
+			}
 			switch inst := inst.(type) {
 			case *Constant:
-				inst.dest.value = inst.val
+				vm.frame.values[inst.dest] = inst.val
 
 			case *Binary:
 				switch inst.op {
 				case Add:
-					inst.dest.value = inst.a.value + inst.b.value
+					vm.frame.values[inst.dest] = wrap(vm.get(inst.a)+vm.get(inst.b), inst.width, inst.signed)
 				}
 
+			case *Extend:
+				vm.frame.values[inst.dest] = wrap(vm.get(inst.src), inst.width, inst.signed)
+
 			case *Copy:
-				inst.dest.value = inst.src.value
+				vm.frame.values[inst.dest] = vm.get(inst.src)
+
+			case *WriteText:
+				if vm.Output != nil {
+					io.WriteString(vm.Output, inst.text)
+				}
+
+			case *WriteInt:
+				if vm.Output != nil && inst.unsigned {
+					fmt.Fprint(vm.Output, uint64(vm.get(inst.val)))
+				} else if vm.Output != nil {
+					fmt.Fprint(vm.Output, vm.get(inst.val))
+				}
+
+			case *WriteChar:
+				if vm.Output != nil {
+					vm.Output.Write([]byte{byte(vm.get(inst.val))})
+				}
+
+			case *Address:
+				vm.frame.values[inst.dest] = vm.address(inst.data)
+
+			case *CodeAddress:
+				vm.frame.values[inst.dest] = vm.codeAddress(inst.block)
+
+			case *Write:
+				bytes, err := vm.ReadMemory(vm.get(inst.addr), vm.get(inst.length))
+				if err != nil {
+					return 0, err
+				}
+				if vm.Output != nil {
+					vm.Output.Write(bytes)
+				}
+
+			case *Allocate:
+				if len(vm.heap)+inst.size > HeapSize {
+					return 0, ErrTrap
+				}
+				vm.frame.values[inst.dest] = heapStart + len(vm.heap)
+				vm.heap = append(vm.heap, make([]byte, inst.size)...)
+
+			case *Alloca:
+				vm.frame.values[inst.dest] = vm.alloca(inst)
+
+			case *Load:
+				if inst.volatile {
+					addr := vm.get(inst.addr) + vm.get(inst.index)*inst.width
+					vm.frame.values[inst.dest] = truncate(vm.peripheral(addr).Load(inst.width), inst.width)
+					break
+				}
+				value, err := vm.load(vm.get(inst.addr), vm.get(inst.index), inst.width)
+				if err != nil {
+					return 0, err
+				}
+				vm.frame.values[inst.dest] = value
+
+			case *Store:
+				if inst.volatile {
+					addr := vm.get(inst.addr) + vm.get(inst.index)*inst.width
+					vm.peripheral(addr).Store(inst.width, truncate(vm.get(inst.val), inst.width))
+					break
+				}
+				if err := vm.store(vm.get(inst.addr), vm.get(inst.index), inst.width, vm.get(inst.val)); err != nil {
+					return 0, err
+				}
 			}
 		}
-		switch branch := block.branch.(type) {
+
+		if err := vm.step(vm.block.branch); err != nil {
+			return 0, err
+		}
+		switch branch := vm.block.branch.(type) {
 		case *Jump:
-			block = branch.target
+			vm.block = branch.target
 
 		case *ConditionalJump:
 			condition := false
+			a, b := vm.get(branch.a), vm.get(branch.b)
 			switch branch.cond {
 			case LessOrEqual:
-				condition = branch.a.value <= branch.b.value
+				condition = a <= b
 			case Greater:
-				condition = branch.a.value > branch.b.value
+				condition = a > b
+			case Above:
+				condition = uint64(a) > uint64(b)
 			case Equal:
-				condition = branch.a.value == branch.b.value
+				condition = a == b
 			}
 			if condition {
-				block = branch.ifTrue
+				vm.block = branch.ifTrue
 			} else {
-				block = branch.ifFalse
+				vm.block = branch.ifFalse
 			}
 
 		case *Exit:
-			fmt.Println(branch.val.value)
-			return
+			return vm.get(branch.val), nil
 
 		case *Call:
-			stack = append(stack, branch.ret)
-			block = branch.target
+			target := branch.target
+			if branch.fn != nil {
+				addr := vm.get(branch.fn) - codeStart
+				if addr < 0 || addr >= len(vm.code) {
+					return 0, fmt.Errorf("call to invalid code address %d", vm.get(branch.fn))
+				}
+				target = vm.code[addr]
+			}
+			vm.frames = append(vm.frames, vm.frame)
+			vm.frame = frame{branch.ret, len(vm.stack), map[*Alloca]int{}, map[*Value]int{}}
+			vm.block = target
 
 		case *Return:
-			block = stack[len(stack)-1]
-			stack = stack[:len(stack)-1]
+			if len(vm.frames) == 0 {
+				return 0, fmt.Errorf("return from block '%s' with empty call stack", vm.block.name)
+			}
+			caller := vm.frames[len(vm.frames)-1]
+			for _, val := range branch.vals {
+				caller.values[val] = vm.get(val)
+			}
+			vm.block = vm.frame.ret
+			vm.stack = vm.stack[:vm.frame.base]
+			vm.frame = caller
+			vm.frames = vm.frames[:len(vm.frames)-1]
+
+		case *Trap:
+			return 0, ErrTrap
+
+		default:
+			return 0, fmt.Errorf("block '%s' has no branch", vm.block.name)
 		}
 	}
 }
+
+func truncate(value, width int) int {
+	if width >= WordSize {
+		return value
+	}
+	return value & (1<<(8*width) - 1)
+}
+
+// wrap truncates value to width bytes and sign extends it when signed, the
+// two's complement overflow of the sized integer types.
+func wrap(value, width int, signed bool) int {
+	value = truncate(value, width)
+	if signed && width < WordSize && value >= 1<<(8*width-1) {
+		value -= 1 << (8 * width)
+	}
+	return value
+}
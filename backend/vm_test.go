@@ -0,0 +1,83 @@
+package backend
+
+import (
+	"errors"
+	"testing"
+)
+
+// counter builds a program that adds one to a counter until it equals limit
+// and exits with it, returning the entry block, the loop block and the
+// counter.
+func counter(limit int) (*Block, *Block, *Value) {
+	program := NewProgram()
+	entry := program.NewBlock()
+	loop := program.NewBlock()
+	exit := program.NewBlock()
+
+	count := entry.Constant(0)
+	entry.Jump(loop)
+
+	loop.Copy(loop.Add(count, loop.Constant(1)), count)
+	loop.JumpIfEqual(count, loop.Constant(limit), exit, loop)
+
+	exit.Exit(count)
+	return entry, loop, count
+}
+
+func TestBreakpointResume(t *testing.T) {
+	entry, loop, count := counter(3)
+	vm := NewVirtualMachine(entry)
+	vm.SetBreakpoint(loop)
+	for i := 0; i < 3; i++ {
+		_, err := vm.Run()
+		breakpoint, ok := err.(BreakpointError)
+		if !ok || breakpoint.Block != loop || vm.Block() != loop {
+			t.Fatalf("expected breakpoint %d at block '%s', got %v", i, loop.Name(), err)
+		}
+		if value := vm.GetValue(count); value != i {
+			t.Errorf("expected the counter to be %d at breakpoint %d, got %d", i, i, value)
+		}
+	}
+	vm.ClearBreakpoint(loop)
+	result, err := vm.Run()
+	if err != nil || result != 3 {
+		t.Errorf("expected the resumed run to exit with 3, got %d and %v", result, err)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	entry, loop, _ := counter(2)
+	vm := NewVirtualMachine(entry)
+	blocks := []*Block{}
+	insts := []interface{}{}
+	vm.Trace = func(block *Block, inst interface{}) {
+		blocks = append(blocks, block)
+		insts = append(insts, inst)
+	}
+	if _, err := vm.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// The entry block runs a constant and a jump, each of the two
+	// iterations runs four instructions and a conditional jump, and the last
+	// block runs the exit.
+	if len(insts) != 13 || vm.Steps() != len(insts) {
+		t.Fatalf("expected 13 traced steps, got %d with %d counted", len(insts), vm.Steps())
+	}
+	if _, ok := insts[0].(*Constant); !ok || blocks[0] != entry {
+		t.Errorf("expected the trace to start with the constant in the entry block, got %s", InstToStr(insts[0]))
+	}
+	if _, ok := insts[len(insts)-1].(*Exit); !ok || blocks[2] != loop {
+		t.Errorf("expected the trace to end with the exit after entering the loop, got %s", InstToStr(insts[len(insts)-1]))
+	}
+}
+
+func TestStepLimit(t *testing.T) {
+	program := NewProgram()
+	entry := program.NewBlock()
+	entry.Jump(entry)
+	vm := NewVirtualMachine(entry)
+	vm.MaxSteps = 100
+	if _, err := vm.Run(); !errors.Is(err, ErrStepLimit) || vm.Steps() != 100 {
+		t.Errorf("expected the step limit after 100 steps, got %v after %d", err, vm.Steps())
+	}
+}
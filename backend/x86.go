@@ -2,16 +2,284 @@ package backend
 
 import "fmt"
 
+// savedRegisters are the registers clobbered by the write syscall and the
+// integer printing routine, which must be preserved around them.
+var savedRegisters = []string{"%rax", "%rcx", "%rdx", "%rsi", "%rdi", "%r8", "%r11"}
+
+const writeIntRoutine = `write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+`
+
+// subRegisters name the low 8, 16 and 32 bits of each allocatable register.
+var subRegisters = map[string][3]string{
+	"%rax": {"%al", "%ax", "%eax"},
+	"%rcx": {"%cl", "%cx", "%ecx"},
+	"%rdx": {"%dl", "%dx", "%edx"},
+	"%rbx": {"%bl", "%bx", "%ebx"},
+	"%rsi": {"%sil", "%si", "%esi"},
+	"%rdi": {"%dil", "%di", "%edi"},
+	"%r8":  {"%r8b", "%r8w", "%r8d"},
+	"%r9":  {"%r9b", "%r9w", "%r9d"},
+	"%r10": {"%r10b", "%r10w", "%r10d"},
+	"%r11": {"%r11b", "%r11w", "%r11d"},
+	"%r12": {"%r12b", "%r12w", "%r12d"},
+	"%r13": {"%r13b", "%r13w", "%r13d"},
+	"%r14": {"%r14b", "%r14w", "%r14d"},
+	"%r15": {"%r15b", "%r15w", "%r15d"},
+}
+
+func subRegister(reg string, width int) string {
+	switch width {
+	case 1:
+		return subRegisters[reg][0]
+	case 2:
+		return subRegisters[reg][1]
+	case 4:
+		return subRegisters[reg][2]
+	}
+	return reg
+}
+
+func memoryOperand(addr, index *Value, width int) string {
+	return fmt.Sprintf("(%s,%s,%d)", addr.register, index.register, width)
+}
+
+const writeUintRoutine = `write_uint:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+`
+
+// extend wraps the low width bytes of src into dest with a sign or zero
+// extending move.
+func extend(src, dest string, width int, signed bool) string {
+	switch {
+	case width == 1 && signed:
+		return fmt.Sprintf("  movsbq %s, %s\n", subRegister(src, 1), dest)
+	case width == 1:
+		return fmt.Sprintf("  movzbq %s, %s\n", subRegister(src, 1), dest)
+	case width == 2 && signed:
+		return fmt.Sprintf("  movswq %s, %s\n", subRegister(src, 2), dest)
+	case width == 2:
+		return fmt.Sprintf("  movzwq %s, %s\n", subRegister(src, 2), dest)
+	case width == 4 && signed:
+		return fmt.Sprintf("  movslq %s, %s\n", subRegister(src, 4), dest)
+	case width == 4:
+		return fmt.Sprintf("  mov %s, %s\n", subRegister(src, 4), subRegister(dest, 4))
+	case src != dest:
+		return fmt.Sprintf("  mov %s, %s\n", src, dest)
+	}
+	return ""
+}
+
+// allocate bumps the used size of the heap by size bytes, trapping when it
+// runs out, and leaves the address of the new storage in dest.
+func allocate(dest string, size int) string {
+	scratch := "%rax"
+	if dest == scratch {
+		scratch = "%rcx"
+	}
+	str := fmt.Sprintf("  mov heap_used(%%rip), %s\n", dest)
+	str += fmt.Sprintf("  add $%d, %s\n", size, dest)
+	str += fmt.Sprintf("  cmp $%d, %s\n", HeapSize, dest)
+	str += "  jbe 1f\n"
+	str += "  ud2\n"
+	str += "1:\n"
+	str += fmt.Sprintf("  mov %s, heap_used(%%rip)\n", dest)
+	str += fmt.Sprintf("  push %s\n", scratch)
+	str += fmt.Sprintf("  lea heap-%d(%%rip), %s\n", size, scratch)
+	str += fmt.Sprintf("  add %s, %s\n", scratch, dest)
+	str += fmt.Sprintf("  pop %s\n", scratch)
+	return str
+}
+
+func saveRegisters() string {
+	str := ""
+	for _, reg := range savedRegisters {
+		str += fmt.Sprintf("  push %s\n", reg)
+	}
+	return str
+}
+
+func restoreRegisters() string {
+	str := ""
+	for i := range savedRegisters {
+		str += fmt.Sprintf("  pop %s\n", savedRegisters[len(savedRegisters)-i-1])
+	}
+	return str
+}
+
+func asciiString(text string) string {
+	str := ""
+	for _, b := range []byte(text) {
+		switch {
+		case b == '"' || b == '\\':
+			str += "\\" + string(b)
+		case b < ' ' || b > '~':
+			str += fmt.Sprintf("\\%03o", b)
+		default:
+			str += string(b)
+		}
+	}
+	return "\"" + str + "\""
+}
+
+// functionBlocks returns the blocks of the function starting at entry, which
+// are reached without entering a call.
+func functionBlocks(entry *Block, visited map[*Block]struct{}) []*Block {
+	blocks := []*Block{}
+	queue := []*Block{entry}
+	for len(queue) > 0 {
+		block := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if _, ok := visited[block]; ok {
+			continue
+		}
+		visited[block] = struct{}{}
+		blocks = append(blocks, block)
+		switch branch := block.branch.(type) {
+		case *Jump:
+			queue = append(queue, branch.target)
+		case *ConditionalJump:
+			queue = append(queue, branch.ifFalse, branch.ifTrue)
+		case *Call:
+			queue = append(queue, branch.ret)
+		}
+	}
+	return blocks
+}
+
+// functionEntries returns the entry blocks of the functions that are called
+// or have their address taken.
+func functionEntries(program *Program) []*Block {
+	entries := []*Block{}
+	for _, block := range program.blocks {
+		for _, inst := range block.instructions {
+			if inst, ok := inst.(*CodeAddress); ok {
+				entries = append(entries, inst.block)
+			}
+		}
+		if call, ok := block.branch.(*Call); ok {
+			entries = append(entries, call.callees()...)
+		}
+	}
+	return entries
+}
+
+// stackFrames lays out the allocas of every function below its frame pointer
+// %rbp, returning their offsets and the 16 byte aligned frame size of each
+// function by its entry block.
+func stackFrames(program *Program, entry *Block) (map[*Alloca]int, map[*Block]int) {
+	entries := append([]*Block{entry}, functionEntries(program)...)
+	offsets := map[*Alloca]int{}
+	sizes := map[*Block]int{}
+	visited := map[*Block]struct{}{}
+	for _, function := range entries {
+		size := 0
+		for _, block := range functionBlocks(function, visited) {
+			for _, inst := range block.instructions {
+				if inst, ok := inst.(*Alloca); ok {
+					size += (inst.size + WordSize - 1) / WordSize * WordSize
+					offsets[inst] = -size
+				}
+			}
+		}
+		if size > 0 {
+			sizes[function] = (size + 15) / 16 * 16
+		}
+	}
+	return offsets, sizes
+}
+
+// savedAcrossCall returns the registers holding the values that are live
+// across call, in a fixed order.
+func savedAcrossCall(call *Call) []string {
+	live := map[string]struct{}{}
+	for val := range call.saved {
+		live[val.register] = struct{}{}
+	}
+	saved := []string{}
+	for _, reg := range registers {
+		if _, ok := live[reg]; ok {
+			saved = append(saved, reg)
+		}
+	}
+	return saved
+}
+
 func X86(program *Program, entry *Block) string {
 	finishedBlocks := map[*Block]struct{}{}
 	str := ""
 	queue := []*Block{entry}
+	texts := []string{}
+	usesWriteInt := false
+	usesWriteUint := false
+	usesHeap := false
+	offsets, frameSizes := stackFrames(program, entry)
+	functions := map[*Block]struct{}{}
+	for _, function := range functionEntries(program) {
+		functions[function] = struct{}{}
+	}
 
 	for len(queue) > 0 {
 		block := queue[len(queue)-1]
-		finishedBlocks[block] = struct{}{}
 		queue = queue[:len(queue)-1]
+		if _, finished := finishedBlocks[block]; finished {
+			continue
+		}
+		finishedBlocks[block] = struct{}{}
 		str += fmt.Sprintf("%s:\n", block.name)
+		if _, ok := functions[block]; ok {
+			str += "  push %rbp\n"
+			str += "  mov %rsp, %rbp\n"
+		} else if block == entry && frameSizes[block] > 0 {
+			str += "  mov %rsp, %rbp\n"
+		}
+		if frameSizes[block] > 0 {
+			str += fmt.Sprintf("  sub $%d, %%rsp\n", frameSizes[block])
+		}
 
 		for _, inst := range block.instructions {
 			switch inst := inst.(type) {
@@ -26,10 +294,82 @@ func X86(program *Program, entry *Block) string {
 					str += fmt.Sprintf("  mov %s, %s\n", inst.a.register, inst.dest.register)
 					str += fmt.Sprintf("  add %s, %s\n", inst.b.register, inst.dest.register)
 				}
+				if inst.width < WordSize {
+					str += extend(inst.dest.register, inst.dest.register, inst.width, inst.signed)
+				}
+			case *Extend:
+				str += extend(inst.src.register, inst.dest.register, inst.width, inst.signed)
 			case *Copy:
 				if inst.src.register != inst.dest.register {
 					str += fmt.Sprintf("  mov %s, %s\n", inst.src.register, inst.dest.register)
 				}
+			case *WriteText:
+				str += saveRegisters()
+				str += "  mov $1, %eax\n"
+				str += "  mov $1, %edi\n"
+				str += fmt.Sprintf("  lea text%d(%%rip), %%rsi\n", len(texts))
+				str += fmt.Sprintf("  mov $%d, %%edx\n", len(inst.text))
+				str += "  syscall\n"
+				str += restoreRegisters()
+				texts = append(texts, inst.text)
+			case *WriteInt:
+				routine := "write_int"
+				if inst.unsigned {
+					routine = "write_uint"
+					usesWriteUint = true
+				} else {
+					usesWriteInt = true
+				}
+				str += saveRegisters()
+				str += fmt.Sprintf("  mov %s, %%rdi\n", inst.val.register)
+				str += fmt.Sprintf("  call %s\n", routine)
+				str += restoreRegisters()
+			case *WriteChar:
+				str += saveRegisters()
+				str += "  sub $8, %rsp\n"
+				str += fmt.Sprintf("  mov %s, (%%rsp)\n", inst.val.register)
+				str += "  mov $1, %eax\n"
+				str += "  mov $1, %edi\n"
+				str += "  mov %rsp, %rsi\n"
+				str += "  mov $1, %edx\n"
+				str += "  syscall\n"
+				str += "  add $8, %rsp\n"
+				str += restoreRegisters()
+			case *Address:
+				str += fmt.Sprintf("  lea %s(%%rip), %s\n", inst.data.name, inst.dest.register)
+			case *CodeAddress:
+				str += fmt.Sprintf("  lea %s(%%rip), %s\n", inst.block.name, inst.dest.register)
+				if _, finished := finishedBlocks[inst.block]; !finished {
+					queue = append(queue, inst.block)
+				}
+			case *Write:
+				str += saveRegisters()
+				str += fmt.Sprintf("  push %s\n", inst.addr.register)
+				str += fmt.Sprintf("  push %s\n", inst.length.register)
+				str += "  pop %rdx\n"
+				str += "  pop %rsi\n"
+				str += "  mov $1, %eax\n"
+				str += "  mov $1, %edi\n"
+				str += "  syscall\n"
+				str += restoreRegisters()
+			case *Allocate:
+				str += allocate(inst.dest.register, inst.size)
+				usesHeap = true
+			case *Alloca:
+				str += fmt.Sprintf("  lea %d(%%rbp), %s\n", offsets[inst], inst.dest.register)
+			case *Load:
+				src := memoryOperand(inst.addr, inst.index, inst.width)
+				switch inst.width {
+				case 1:
+					str += fmt.Sprintf("  movzbq %s, %s\n", src, inst.dest.register)
+				case 2:
+					str += fmt.Sprintf("  movzwq %s, %s\n", src, inst.dest.register)
+				default:
+					str += fmt.Sprintf("  mov %s, %s\n", src, subRegister(inst.dest.register, inst.width))
+				}
+			case *Store:
+				dest := memoryOperand(inst.addr, inst.index, inst.width)
+				str += fmt.Sprintf("  mov %s, %s\n", subRegister(inst.val.register, inst.width), dest)
 			}
 		}
 		switch branch := block.branch.(type) {
@@ -45,6 +385,8 @@ func X86(program *Program, entry *Block) string {
 			case Equal:
 				str += fmt.Sprintf("  je %s\n", branch.ifTrue.name)
 			case Greater:
+				str += fmt.Sprintf("  jg %s\n", branch.ifTrue.name)
+			case Above:
 				str += fmt.Sprintf("  ja %s\n", branch.ifTrue.name)
 			case LessOrEqual:
 				str += fmt.Sprintf("  jle %s\n", branch.ifTrue.name)
@@ -58,11 +400,59 @@ func X86(program *Program, entry *Block) string {
 				queue = append(queue, branch.ifFalse)
 			}
 		case *Exit:
-			str += fmt.Sprintf("  mov %s, %%edi\n", branch.val.register)
+			str += fmt.Sprintf("  mov %s, %%rdi\n", branch.val.register)
 			str += "  mov $60, %eax\n"
 			str += "  syscall\n"
+		case *Call:
+			saved := savedAcrossCall(branch)
+			for _, reg := range saved {
+				str += fmt.Sprintf("  push %s\n", reg)
+			}
+			if branch.fn != nil {
+				str += fmt.Sprintf("  call *%s\n", branch.fn.register)
+			} else {
+				str += fmt.Sprintf("  call %s\n", branch.target.name)
+			}
+			for i := range saved {
+				str += fmt.Sprintf("  pop %s\n", saved[len(saved)-i-1])
+			}
+			for _, target := range branch.callees() {
+				if _, finished := finishedBlocks[target]; !finished {
+					queue = append(queue, target)
+				}
+			}
+			if _, finished := finishedBlocks[branch.ret]; finished {
+				str += fmt.Sprintf("  jmp %s\n", branch.ret.name)
+			} else {
+				queue = append(queue, branch.ret)
+			}
+		case *Return:
+			str += "  leave\n"
+			str += "  ret\n"
+		case *Trap:
+			str += "  ud2\n"
 		}
 	}
 
+	if usesWriteInt {
+		str += writeIntRoutine
+	}
+	if usesWriteUint {
+		str += writeUintRoutine
+	}
+	if usesHeap {
+		str += ".section .bss\n"
+		str += "heap_used:\n  .zero 8\n"
+		str += fmt.Sprintf("heap:\n  .zero %d\n", HeapSize)
+	}
+	if len(texts) > 0 || len(program.data) > 0 {
+		str += ".section .rodata\n"
+		for i, text := range texts {
+			str += fmt.Sprintf("text%d:\n  .ascii %s\n", i, asciiString(text))
+		}
+		for _, data := range program.data {
+			str += fmt.Sprintf("%s:\n  .ascii %s\n", data.name, asciiString(string(data.bytes)))
+		}
+	}
 	return str
 }
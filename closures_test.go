@@ -0,0 +1,37 @@
+package main
+
+import (
+	"language/backend"
+	"testing"
+)
+
+// Environments of function values that are not returned live in the frame
+// that creates them, so a loop can create more of them than the heap holds.
+func TestEnvironmentsInLoops(t *testing.T) {
+	source := `add = fn (a: int) -> int a + 1
+k = 3
+run = fn (n: int) -> int {
+    h = if (n < 5) add else fn (a: int) -> int a + n
+    h(n)
+}
+i = 0
+total = 0
+while (i < 150000) {
+    g = if (i < 5) add else fn (a: int) -> int a + k
+    total = g(total) + run(i)
+    i = i + 1
+}
+total`
+	comp, errs := compile("", source)
+	if comp == nil {
+		t.Fatalf("compilation failed: %v", errs)
+	}
+	vm := backend.NewVirtualMachine(comp.entry)
+	result, err := vm.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 22500299985; result != want {
+		t.Errorf("expected %d, got %d", want, result)
+	}
+}
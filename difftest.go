@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"language/backend"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+)
+
+type mismatch struct {
+	path   string
+	vm     string
+	native string
+	comp   *compilation
+}
+
+func diffTest(dir string) bool {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+	sort.Strings(paths)
+	if len(paths) == 0 {
+		fmt.Printf("no programs found in '%s'\n", dir)
+		return false
+	}
+
+	tmp, err := ioutil.TempDir("", "difftest")
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+	defer os.RemoveAll(tmp)
+
+	failed := 0
+	for _, path := range paths {
+		m := diffProgram(path, tmp)
+		if m == nil {
+			fmt.Printf("ok   %s\n", path)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL %s\n", path)
+		fmt.Printf("  vm:     %s\n", m.vm)
+		fmt.Printf("  native: %s\n", m.native)
+		if m.comp != nil {
+			fmt.Printf("--- ir ---\n%s", m.comp.ir)
+			fmt.Printf("--- asm ---\n%s\n", m.comp.asm)
+		}
+	}
+	fmt.Printf("%d/%d programs agree\n", len(paths)-failed, len(paths))
+	return failed == 0
+}
+
+func diffProgram(path, tmp string) (m *mismatch) {
+	m = &mismatch{path: path}
+	defer func() {
+		if r := recover(); r != nil {
+			m.vm = fmt.Sprintf("compiler panic: %v", r)
+			m.native = "not run"
+		}
+	}()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		m.vm, m.native = err.Error(), "not run"
+		return m
+	}
+	comp, errs := compile(filepath.Dir(path), string(data))
+	if comp == nil {
+		m.vm, m.native = fmt.Sprintf("compilation failed: %v", errs), "not run"
+		return m
+	}
+	m.comp = comp
+
+	vm := backend.NewVirtualMachine(comp.entry)
+	vm.MaxSteps = 1000000
+	vmOutput := &bytes.Buffer{}
+	vm.Output = vmOutput
+	result, vmErr := vm.Run()
+	code, nativeOutput, nativeErr := runNative(comp.asm, tmp)
+
+	switch {
+	case vmErr == backend.ErrTrap:
+		m.vm = fmt.Sprintf("trap, output %q", vmOutput)
+	case vmErr != nil:
+		m.vm = vmErr.Error()
+	default:
+		m.vm = fmt.Sprintf("exit %d, output %q", result&0xff, vmOutput)
+	}
+	switch {
+	case nativeErr == backend.ErrTrap:
+		m.native = fmt.Sprintf("trap, output %q", nativeOutput)
+	case nativeErr != nil:
+		m.native = nativeErr.Error()
+	default:
+		m.native = fmt.Sprintf("exit %d, output %q", code, nativeOutput)
+	}
+
+	if (vmErr == nil || vmErr == backend.ErrTrap) && (nativeErr == nil || nativeErr == backend.ErrTrap) && m.vm == m.native {
+		return nil
+	}
+	return m
+}
+
+func runNative(asm, tmp string) (int, string, error) {
+	src := filepath.Join(tmp, "output.s")
+	bin := filepath.Join(tmp, "output")
+	if err := ioutil.WriteFile(src, []byte(asm), 0644); err != nil {
+		return 0, "", err
+	}
+	if out, err := exec.Command("gcc", "-nostdlib", src, "-o", bin).CombinedOutput(); err != nil {
+		return 0, "", fmt.Errorf("gcc failed: %s", out)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Stdout = output
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return 0, "", fmt.Errorf("timed out")
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signal() == syscall.SIGILL {
+			return 0, output.String(), backend.ErrTrap
+		}
+		if exitErr.ExitCode() < 0 {
+			return 0, "", fmt.Errorf("killed: %s", exitErr)
+		}
+		return exitErr.ExitCode(), output.String(), nil
+	}
+	return 0, output.String(), err
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"language/syntax"
+)
+
+func formatFiles(paths []string, check bool) bool {
+	ok := true
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Println(err)
+			ok = false
+			continue
+		}
+		formatted, errs := syntax.Format(string(data))
+		if len(errs) != 0 {
+			for _, err := range errs {
+				fmt.Printf("%s:%s\n", path, err)
+			}
+			ok = false
+			continue
+		}
+		if formatted == string(data) {
+			continue
+		}
+		if check {
+			fmt.Println(path)
+			ok = false
+			continue
+		}
+		if err := ioutil.WriteFile(path, []byte(formatted), 0644); err != nil {
+			fmt.Println(err)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,110 @@
+package frontend
+
+import (
+	"fmt"
+	"language/syntax"
+)
+
+// annotatedType builds a type with fresh values from a type annotation such
+// as int, bool?, str, (u8, int) or the name of an enum.
+func (comp *compiler) annotatedType(span syntax.Span) Type {
+	defer comp.at(span)()
+	switch expr := span.GetExpr().(type) {
+	case syntax.Identifier:
+		switch expr.Ident {
+		case "bool":
+			return Boolean{comp.program.NewValue()}
+		case "str":
+			return String{comp.program.NewValue(), comp.program.NewValue()}
+		}
+		if kind, ok := intKinds[expr.Ident]; ok {
+			return Integer{comp.program.NewValue(), kind, nil}
+		}
+		if enum, ok := comp.scope.get(expr.Ident).(EnumType); ok {
+			comp.use(span, expr.Ident)
+			return comp.instance(enum)
+		}
+		comp.throw(fmt.Errorf("unknown type '%s'", expr.Ident))
+		return nil
+
+	case syntax.Unary:
+		if expr.Op == syntax.Maybe {
+			ty := comp.annotatedType(expr.Expr)
+			if ty == nil {
+				return nil
+			}
+			return Maybe{comp.program.NewValue(), ty}
+		}
+
+	case syntax.Tuple:
+		items := make([]Type, len(expr.Items))
+		for i, item := range expr.Items {
+			if items[i] = comp.annotatedType(item); items[i] == nil {
+				return nil
+			}
+		}
+		return Tuple{items}
+	}
+	comp.throw(fmt.Errorf("invalid type annotation"))
+	return nil
+}
+
+// annotatedParams builds the parameter type of a function when every
+// parameter is annotated, returning nil when one is not.
+func (comp *compiler) annotatedParams(span syntax.Span) (Type, bool) {
+	switch expr := span.GetExpr().(type) {
+	case syntax.Binary:
+		if expr.Op == syntax.Annotation {
+			ty := comp.annotatedType(expr.Right)
+			return ty, ty != nil
+		}
+	case syntax.Tuple:
+		items := make([]Type, len(expr.Items))
+		for i, item := range expr.Items {
+			ty, ok := comp.annotatedParams(item)
+			if ty == nil {
+				return nil, ok
+			}
+			items[i] = ty
+		}
+		return Tuple{items}, true
+	}
+	return nil, true
+}
+
+// conform checks that ty has the expected type, giving unsuffixed integer
+// literals the expected kind and wrapping values that are expected to be
+// maybes.
+func (comp *compiler) conform(ty, expected Type) (Type, bool) {
+	if ty.Type() == expected.Type() {
+		return ty, true
+	}
+	switch expected := expected.(type) {
+	case Integer:
+		if integer, ok := ty.(Integer); ok && integer.literal != nil {
+			return comp.coerceInteger(integer, expected.kind)
+		}
+	case Maybe:
+		val := comp.block.Constant(1)
+		if maybe, ok := ty.(Maybe); ok {
+			val, ty = maybe.val, maybe.ty
+		}
+		inner, ok := comp.conform(ty, expected.ty)
+		if !ok {
+			return nil, false
+		}
+		return Maybe{val, inner}, true
+	case Tuple:
+		if tuple, ok := ty.(Tuple); ok && len(tuple.items) == len(expected.items) {
+			items := make([]Type, len(tuple.items))
+			for i, item := range tuple.items {
+				if items[i], ok = comp.conform(item, expected.items[i]); !ok {
+					return nil, false
+				}
+			}
+			return Tuple{items}, true
+		}
+	}
+	comp.throw(fmt.Errorf("expected '%s' instead of '%s'", expected.Type(), ty.Type()))
+	return nil, false
+}
@@ -0,0 +1,212 @@
+package frontend
+
+import (
+	"fmt"
+	"language/backend"
+	"language/syntax"
+)
+
+func (comp *compiler) compileArray(expr syntax.Array) Type {
+	if len(expr.Items) == 0 {
+		comp.throw(fmt.Errorf("cannot infer the element type of an empty array"))
+		return nil
+	}
+	items := make([]Type, len(expr.Items))
+	for i, item := range expr.Items {
+		ty := comp.compile(item)
+		if ty == nil {
+			return nil
+		}
+		if i > 0 && ty.Type() != items[0].Type() && !(isFunc(ty) && isFunc(items[0])) {
+			comp.throw(fmt.Errorf("array items have different types '%s' and '%s'", items[0].Type(), ty.Type()))
+			return nil
+		}
+		items[i] = ty
+	}
+	if isFunc(items[0]) {
+		blocks := make([]*backend.Block, len(items))
+		for i := range blocks {
+			blocks[i] = comp.block
+		}
+		var ok bool
+		if items, ok = comp.unifyFuncs(items, blocks); !ok {
+			return nil
+		}
+	}
+	words := len(ToValues(items[0]))
+	if words == 0 {
+		comp.throw(fmt.Errorf("cannot store '%s' in an array", items[0].Type()))
+		return nil
+	}
+	addr := comp.block.Alloca(len(items) * words * backend.WordSize)
+	comp.arrays = true
+	for i, item := range items {
+		for k, val := range ToValues(item) {
+			comp.block.Store(addr, comp.block.Constant(i*words+k), val, backend.WordSize)
+		}
+	}
+	return Array{addr, items[0], len(items)}
+}
+
+// holds reports whether a value of type ty is, or is made of, a value whose
+// type match accepts, looking through maybes, structs, tuples, enum payloads
+// and the variables that functions captured.
+func holds(ty Type, match func(Type) bool) bool {
+	if match(ty) {
+		return true
+	}
+	switch ty := ty.(type) {
+	case Maybe:
+		return holds(ty.ty, match)
+	case Struct:
+		for _, item := range ty.dict {
+			if holds(item, match) {
+				return true
+			}
+		}
+	case Tuple:
+		for _, item := range ty.items {
+			if holds(item, match) {
+				return true
+			}
+		}
+	case Enum:
+		for _, variant := range ty.variants {
+			if variant.payload != nil && holds(variant.payload, match) {
+				return true
+			}
+		}
+	case Func:
+		return holds(ty.env, match)
+	}
+	return false
+}
+
+// holdsArray reports whether a value of type ty can refer to the storage of
+// an array, which lives in the stack frame of the function that created it.
+// A function value chosen at runtime hides what it captured, so it counts
+// if any function converted to its signature captured an array.
+func holdsArray(ty Type) bool {
+	return holds(ty, func(ty Type) bool {
+		switch ty := ty.(type) {
+		case Array:
+			return true
+		case FuncRef:
+			return ty.sig.arrays
+		}
+		return false
+	})
+}
+
+func (comp *compiler) compileIndexValue(span syntax.Span) (*backend.Value, bool) {
+	ty := comp.compile(span)
+	if ty == nil {
+		return nil, false
+	}
+	index, ok := ty.(Integer)
+	if !ok {
+		comp.throw(fmt.Errorf("cannot index with '%s'", ty.Type()))
+		return nil, false
+	}
+	return index.val, true
+}
+
+// element compiles the array and index of an indexing expression, trapping
+// when the index is out of bounds, and returns the word index of the first
+// value of the element.
+func (comp *compiler) element(array Array, span syntax.Span) (*backend.Value, bool) {
+	index, ok := comp.compileIndexValue(span)
+	if !ok {
+		return nil, false
+	}
+	comp.checkBounds(index, comp.block.Constant(array.length))
+	offset := index
+	for i := 1; i < len(ToValues(array.elem)); i++ {
+		offset = comp.block.Add(offset, index)
+	}
+	return offset, true
+}
+
+func (comp *compiler) checkBounds(index, length *backend.Value) {
+	belowBlock := comp.program.NewBlock()
+	inBlock := comp.program.NewBlock()
+	trapBlock := comp.program.NewBlock()
+	comp.block.JumpIfGreater(length, index, belowBlock, trapBlock)
+	belowBlock.JumpIfLessOrEqual(belowBlock.Constant(0), index, inBlock, trapBlock)
+	trapBlock.Trap()
+	comp.block = inBlock
+}
+
+func (comp *compiler) loadElement(array Array, offset *backend.Value) Type {
+	vals := ToValues(array.elem)
+	for k := range vals {
+		index := offset
+		if k > 0 {
+			index = comp.block.Add(offset, comp.block.Constant(k))
+		}
+		vals[k] = comp.block.Load(array.addr, index, backend.WordSize)
+	}
+	return FromValues(vals, array.elem)
+}
+
+func (comp *compiler) storeElement(array Array, offset *backend.Value, ty Type) {
+	for k, val := range ToValues(ty) {
+		index := offset
+		if k > 0 {
+			index = comp.block.Add(offset, comp.block.Constant(k))
+		}
+		comp.block.Store(array.addr, index, val, backend.WordSize)
+	}
+}
+
+func (comp *compiler) compileIndex(expr syntax.Binary) Type {
+	ty := comp.compile(expr.Left)
+	if ty == nil {
+		return nil
+	}
+	switch ty := ty.(type) {
+	case Array:
+		offset, ok := comp.element(ty, expr.Right)
+		if !ok {
+			return nil
+		}
+		return comp.loadElement(ty, offset)
+
+	case String:
+		index, ok := comp.compileIndexValue(expr.Right)
+		if !ok {
+			return nil
+		}
+		comp.checkBounds(index, ty.length)
+		return Integer{comp.block.Load(ty.addr, index, 1), intKind, nil}
+	}
+	comp.throw(fmt.Errorf("cannot index '%s'", ty.Type()))
+	return nil
+}
+
+func (comp *compiler) matchIndex(expr syntax.Binary, ty Type) bool {
+	left := comp.compile(expr.Left)
+	if left == nil {
+		return false
+	}
+	array, ok := left.(Array)
+	if !ok {
+		comp.throw(fmt.Errorf("cannot assign to an element of '%s'", left.Type()))
+		return false
+	}
+	offset, ok := comp.element(array, expr.Right)
+	if !ok {
+		return false
+	}
+	if fn, ok := ty.(Func); ok && isFunc(array.elem) {
+		if ty, ok = comp.funcRef(fn); !ok {
+			return false
+		}
+	}
+	if ty.Type() != array.elem.Type() {
+		comp.throw(fmt.Errorf("cannot assign '%s' to an element of '%s'", ty.Type(), array.Type()))
+		return false
+	}
+	comp.storeElement(array, offset, ty)
+	return true
+}
@@ -0,0 +1,103 @@
+package frontend
+
+import (
+	"fmt"
+	"language/syntax"
+)
+
+// builtin returns the builtin function called by callee, unless a variable
+// of the same name shadows it.
+func (comp *compiler) builtin(callee syntax.Span) func(comp *compiler, args syntax.Span) Type {
+	ident, ok := callee.GetExpr().(syntax.Identifier)
+	if !ok || comp.scope.get(ident.Ident) != nil {
+		return nil
+	}
+	switch ident.Ident {
+	case "print":
+		return compilePrint
+	case "putc":
+		return compilePutc
+	case "exit":
+		return compileExit
+	case "write":
+		return compileWrite
+	case "len":
+		return compileLen
+	}
+	if kind, ok := intKinds[ident.Ident]; ok {
+		return convertInteger(kind)
+	}
+	return nil
+}
+
+func (comp *compiler) compileInteger(span syntax.Span, name string) (Integer, bool) {
+	ty := comp.compile(span)
+	if ty == nil {
+		return Integer{}, false
+	}
+	integer, ok := ty.(Integer)
+	if !ok {
+		comp.throw(fmt.Errorf("expected an integer argument to '%s' instead of '%s'", name, ty.Type()))
+		return Integer{}, false
+	}
+	return integer, true
+}
+
+func (comp *compiler) compileString(span syntax.Span, name string) (String, bool) {
+	ty := comp.compile(span)
+	if ty == nil {
+		return String{}, false
+	}
+	str, ok := ty.(String)
+	if !ok {
+		comp.throw(fmt.Errorf("expected a string argument to '%s' instead of '%s'", name, ty.Type()))
+		return String{}, false
+	}
+	return str, true
+}
+
+func compilePrint(comp *compiler, args syntax.Span) Type {
+	ty := comp.compile(args)
+	if ty == nil {
+		return nil
+	}
+	comp.write(ty)
+	comp.block.WriteText("\n")
+	return ty
+}
+
+func compilePutc(comp *compiler, args syntax.Span) Type {
+	char, ok := comp.compileInteger(args, "putc")
+	if !ok {
+		return nil
+	}
+	comp.block.WriteChar(char.val)
+	return char
+}
+
+func compileExit(comp *compiler, args syntax.Span) Type {
+	code, ok := comp.compileInteger(args, "exit")
+	if !ok {
+		return nil
+	}
+	comp.block.Exit(code.val)
+	comp.block = comp.program.NewBlock()
+	return code
+}
+
+func compileWrite(comp *compiler, args syntax.Span) Type {
+	str, ok := comp.compileString(args, "write")
+	if !ok {
+		return nil
+	}
+	comp.block.Write(str.addr, str.length)
+	return str
+}
+
+func compileLen(comp *compiler, args syntax.Span) Type {
+	str, ok := comp.compileString(args, "len")
+	if !ok {
+		return nil
+	}
+	return Integer{str.length, intKind, nil}
+}
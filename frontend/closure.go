@@ -0,0 +1,84 @@
+package frontend
+
+import "language/syntax"
+
+// freeNames collects every name that span refers to, leaving out the field
+// names after '.' and the names of types in annotations.
+func freeNames(span syntax.Span, names map[string]struct{}) {
+	switch expr := span.GetExpr().(type) {
+	case syntax.Identifier:
+		names[expr.Ident] = struct{}{}
+	case syntax.Unary:
+		freeNames(expr.Expr, names)
+	case syntax.Binary:
+		freeNames(expr.Left, names)
+		switch expr.Op {
+		case syntax.Dot:
+			fieldNames(expr.Right, names)
+		case syntax.Annotation, syntax.Returns:
+		default:
+			freeNames(expr.Right, names)
+		}
+	case syntax.Block:
+		for _, statement := range expr.Statements {
+			freeNames(statement, names)
+		}
+	case syntax.Tuple:
+		for _, item := range expr.Items {
+			freeNames(item, names)
+		}
+	case syntax.Array:
+		for _, item := range expr.Items {
+			freeNames(item, names)
+		}
+	case syntax.Struct:
+		freeNames(expr.Block, names)
+	case syntax.Match:
+		freeNames(expr.Value, names)
+		freeNames(expr.Block, names)
+	case syntax.Return:
+		freeNames(expr.Value, names)
+	}
+}
+
+// fieldNames collects the names that the right of '.' refers to. It starts
+// with a field name, which is left out, but the arguments of a call such as
+// m.f(y) are ordinary expressions.
+func fieldNames(span syntax.Span, names map[string]struct{}) {
+	expr, ok := span.GetExpr().(syntax.Binary)
+	if !ok {
+		if _, field := span.GetExpr().(syntax.Identifier); !field {
+			freeNames(span, names)
+		}
+		return
+	}
+	switch expr.Op {
+	case syntax.Call, syntax.Index:
+		fieldNames(expr.Left, names)
+		freeNames(expr.Right, names)
+	case syntax.Dot:
+		fieldNames(expr.Left, names)
+		fieldNames(expr.Right, names)
+	default:
+		freeNames(span, names)
+	}
+}
+
+// capture copies the variables that the body of a function refers to into
+// an environment when the function is created, so that calls see their
+// values at that point rather than whatever they hold at the call. Names
+// that are not defined yet, such as that of the function itself, are still
+// looked up when the body is compiled.
+func (comp *compiler) capture(body syntax.Span) Struct {
+	names := map[string]struct{}{}
+	freeNames(body, names)
+	env := Struct{map[string]Type{}, map[string]syntax.Span{}}
+	for name := range names {
+		if ty := comp.scope.get(name); ty != nil {
+			env.dict[name] = ty
+		}
+	}
+	snapshot := comp.Duplicate(env)
+	comp.Copy(env, snapshot)
+	return snapshot.(Struct)
+}
@@ -4,35 +4,135 @@ import (
 	"fmt"
 	"language/backend"
 	"language/syntax"
-	"strconv"
 )
 
 type compiler struct {
-	program *backend.Program
-	block   *backend.Block
-	scope   *scope
-	errors  []error
+	program   *backend.Program
+	block     *backend.Block
+	scope     *scope
+	errors    []error
+	span      syntax.Span
+	statement syntax.Span
+	info      *Info
+
+	signatures map[string]*signature
+	modules    map[string]Struct
+
+	loop    *loop
+	returns *[]exit
+	// arrays is set once the function being compiled stores an array in
+	// its stack frame, and envs holds the storage of the environments of
+	// the function values it creates.
+	arrays bool
+	envs   []storage
+	// later counts the names bound by the statements after the current one
+	// in its block, and pending holds the annotated functions that use one
+	// of them, which are compiled once it is bound.
+	later   map[string]int
+	pending []pending
 }
 
-func Compile(span syntax.Span) (*backend.Program, *backend.Block, *backend.Block, Type, []error) {
+// storage is the address of stack storage allocated in block.
+type storage struct {
+	block *backend.Block
+	addr  *backend.Value
+}
+
+// pending is an annotated function bound in scope before names that its
+// body uses, which are bound later in the same block.
+type pending struct {
+	fn    Func
+	scope *scope
+	names map[string]struct{}
+}
+
+func newCompiler(program *backend.Program, entry *backend.Block) *compiler {
+	return &compiler{
+		program: program,
+		block:   entry,
+		scope:   newScope(),
+		errors:  []error{},
+		info:    &Info{},
+
+		signatures: map[string]*signature{},
+		modules:    map[string]Struct{},
+	}
+}
+
+// Compile compiles span into a new program. The modules it imports are
+// compiled first, in the order given, so each module must come after the
+// modules it imports.
+func Compile(span syntax.Span, modules ...Module) (*backend.Program, *backend.Block, *backend.Block, Type, []error) {
 	program := backend.NewProgram()
 	entry := program.NewBlock()
 	entry.SetName("_start")
-	compiler := &compiler{program, entry, newScope(), []error{}}
+	compiler := newCompiler(program, entry)
+	if _, ok := compiler.compileModules(modules); !ok {
+		return program, entry, compiler.block, nil, compiler.errors
+	}
 	ty := compiler.compile(span)
 	return program, entry, compiler.block, ty, compiler.errors
 }
 
 func (comp *compiler) throw(err error) {
-	comp.errors = append(comp.errors, err)
+	comp.errors = append(comp.errors, compileError{comp.span, err})
+}
+
+// at makes span the location reported by errors until the returned function
+// restores the previous one.
+func (comp *compiler) at(span syntax.Span) func() {
+	outer := comp.span
+	comp.span = span
+	return func() {
+		comp.span = outer
+	}
+}
+
+func (comp *compiler) use(span syntax.Span, name string) {
+	if def, ok := comp.scope.getDef(name); ok {
+		comp.info.Definitions = append(comp.info.Definitions, Definition{span, def})
+	}
+}
+
+func (comp *compiler) define(span syntax.Span, name string, ty Type) {
+	comp.info.Types = append(comp.info.Types, TypeInfo{span, ty})
+	def, ok := comp.scope.getDef(name)
+	if comp.scope.structure {
+		def, ok = comp.scope.defs[name]
+	}
+	if ok {
+		comp.info.Definitions = append(comp.info.Definitions, Definition{span, def})
+		return
+	}
+	comp.scope.defs[name] = span
+	kind := VariableSymbol
+	if comp.scope.structure {
+		kind = FieldSymbol
+	}
+	statement := comp.statement
+	if statement.Start().Offset() > span.Start().Offset() || statement.End().Offset() < span.End().Offset() {
+		statement = span
+	}
+	comp.info.Symbols = append(comp.info.Symbols, Symbol{name, kind, span, statement})
 }
 
 func (comp *compiler) compile(span syntax.Span) Type {
+	defer comp.at(span)()
+	ty := comp.compileExpr(span)
+	if ty != nil {
+		comp.info.Types = append(comp.info.Types, TypeInfo{span, ty})
+	}
+	return ty
+}
+
+func (comp *compiler) compileExpr(span syntax.Span) Type {
 	switch expr := span.GetExpr().(type) {
 
+	case syntax.Error:
+		return nil
+
 	case syntax.IntegerLiteral:
-		value, _ := strconv.Atoi(expr.Value)
-		return Integer{comp.block.Constant(value)}
+		return comp.compileIntegerLiteral(expr.Value)
 
 	case syntax.BooleanLiteral:
 		if expr.Value == "true" {
@@ -40,12 +140,25 @@ func (comp *compiler) compile(span syntax.Span) Type {
 		}
 		return Boolean{comp.block.Constant(0)}
 
+	case syntax.StringLiteral:
+		value, _ := syntax.Unquote(expr.Value)
+		data := comp.program.NewData([]byte(value))
+		return String{comp.block.Address(data), comp.block.Constant(len(value))}
+
+	case syntax.CharLiteral:
+		value, _ := syntax.Unquote(expr.Value)
+		return Integer{comp.block.Constant(int(value[0])), intKind, nil}
+
 	case syntax.Identifier:
 		ty := comp.scope.get(expr.Ident)
 		if ty == nil {
 			comp.throw(fmt.Errorf("undefined variable '%s'", expr.Ident))
 			return nil
 		}
+		comp.use(span, expr.Ident)
+		if reg, ok := ty.(Mmio); ok {
+			return comp.readMmio(reg)
+		}
 		return ty
 
 	case syntax.Tuple:
@@ -59,17 +172,55 @@ func (comp *compiler) compile(span syntax.Span) Type {
 		}
 		return Tuple{items}
 
+	case syntax.Array:
+		return comp.compileArray(expr)
+
+	case syntax.Mmio:
+		return comp.compileMmio(expr)
+
+	case syntax.Import:
+		return comp.compileImport(expr)
+
+	case syntax.Enum:
+		return comp.compileEnum(expr)
+
+	case syntax.Match:
+		return comp.compileMatch(expr)
+
+	case syntax.Return:
+		return comp.compileReturn(expr)
+
+	case syntax.Break:
+		return comp.compileJump(span, "break")
+
+	case syntax.Continue:
+		return comp.compileJump(span, "continue")
+
 	case syntax.Struct:
 		comp.scope = comp.scope.newScope()
+		comp.scope.structure = true
 		if comp.compile(expr.Block) == nil {
 			return nil
 		}
-		structure := Struct{comp.scope.dict}
+		structure := Struct{comp.scope.dict, comp.scope.defs}
 		comp.scope = comp.scope.previous
 		return structure
 
 	case syntax.Block:
+		outer := comp.later
+		defer func() {
+			comp.later = outer
+		}()
+		comp.later = map[string]int{}
+		for _, line := range expr.Statements {
+			if name, ok := boundName(line); ok {
+				comp.later[name]++
+			}
+		}
 		for _, line := range expr.Statements[:len(expr.Statements)-1] {
+			if name, ok := boundName(line); ok {
+				comp.later[name]--
+			}
 			if comp.compile(line) == nil {
 				return nil
 			}
@@ -85,24 +236,34 @@ func (comp *compiler) compile(span syntax.Span) Type {
 
 	case syntax.Binary:
 		switch expr.Op {
+		case syntax.Index:
+			return comp.compileIndex(expr)
+
 		case syntax.Dot:
 			left := comp.compile(expr.Left)
 			if left == nil {
 				return nil
 			}
+			if enum, ok := left.(EnumType); ok {
+				return comp.compileVariant(enum, expr.Right)
+			}
 			structure, ok := left.(Struct)
 			if !ok {
 				comp.throw(fmt.Errorf("cannot use '.' operator on non-structure '%s'", left.Type()))
+				return nil
 			}
 			comp.scope = comp.scope.newScope()
 			for name, ty := range structure.dict {
 				comp.scope.dict[name] = ty
 			}
+			for name, def := range structure.defs {
+				comp.scope.defs[name] = def
+			}
 			right := comp.compile(expr.Right)
 			if right == nil {
 				return nil
 			}
-			newStruct := Struct{comp.scope.dict}
+			newStruct := Struct{comp.scope.dict, comp.scope.defs}
 			comp.scope = comp.scope.previous
 			if !comp.match(expr.Left, newStruct) {
 				return nil
@@ -110,18 +271,18 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			return right
 
 		case syntax.LessThan:
-			entryBlock := comp.block
 			condBlock := comp.program.NewBlock()
+			falseBlock := comp.program.NewBlock()
 			exitBlock := comp.program.NewBlock()
-			if !comp.compileBoolExpr(span, condBlock, exitBlock) {
+			if !comp.compileBoolExpr(span, condBlock, falseBlock) {
 				return nil
 			}
-			comp.block = exitBlock
 			dest := comp.program.NewValue()
-			comp.block.Copy(condBlock.Constant(1), dest)
-			comp.block.Copy(entryBlock.Constant(0), dest)
-			entryBlock.Jump(exitBlock)
+			condBlock.Copy(condBlock.Constant(1), dest)
+			falseBlock.Copy(falseBlock.Constant(0), dest)
 			condBlock.Jump(exitBlock)
+			falseBlock.Jump(exitBlock)
+			comp.block = exitBlock
 			return Boolean{dest}
 
 		case syntax.Add:
@@ -133,7 +294,7 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			leftInteger, leftIs := left.(Integer)
 			rightInteger, rightIs := right.(Integer)
 			if leftIs && rightIs {
-				return Integer{comp.block.Add(leftInteger.val, rightInteger.val)}
+				return comp.addIntegers(leftInteger, rightInteger)
 			}
 			comp.throw(fmt.Errorf("incompatiable types for addition"))
 			return nil
@@ -143,7 +304,11 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			if right == nil {
 				return nil
 			}
-			if !comp.match(expr.Left, right) {
+			outer := comp.statement
+			comp.statement = span
+			matched := comp.match(expr.Left, right)
+			comp.statement = outer
+			if !matched {
 				return nil
 			}
 			return right
@@ -162,6 +327,7 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			if ty == nil {
 				return nil
 			}
+			condBlock = comp.block
 
 			comp.block = exitBlock
 			if !comp.mergeScopes(elseBlock, condBlock) {
@@ -187,12 +353,13 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			condBlock := comp.program.NewBlock()
 			elseBlock := comp.program.NewBlock()
 			exitBlock := comp.program.NewBlock()
-			comp.block.JumpIfEqual(maybe.val, comp.block.Constant(0), condBlock, exitBlock)
+			comp.block.JumpIfEqual(maybe.val, comp.block.Constant(0), condBlock, elseBlock)
 			comp.block = condBlock
 			ty := comp.compile(expr.Right)
 			if ty == nil {
 				return nil
 			}
+			condBlock = comp.block
 			comp.block = exitBlock
 			if !comp.mergeScopes(elseBlock, condBlock) {
 				return nil
@@ -214,14 +381,31 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			if !comp.compileBoolExpr(expr.Left, startBlock, exitBlock) {
 				return nil
 			}
+			outer := comp.loop
+			defer func() {
+				comp.loop = outer
+			}()
 			comp.scope = comp.scope.newScope()
+			comp.loop = &loop{scope: comp.scope}
 			comp.block = startBlock
 			if comp.compile(expr.Right) == nil {
 				return nil
 			}
+			first := comp.loop
+			base := map[string]Type{}
+			for name := range comp.scope.dict {
+				base[name] = comp.scope.previous.get(name)
+			}
+			for _, exit := range first.continues {
+				if !comp.joinExit(exit, base) {
+					return nil
+				}
+			}
+			comp.block.Jump(condBlock)
 
 			comp.block = condBlock
 			comp.scope = comp.scope.newScope()
+			comp.loop = &loop{scope: comp.scope}
 
 			comp.block = condBlock
 			if !comp.compileBoolExpr(expr.Left, bodyBlock, finalBlock) {
@@ -233,44 +417,90 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			if ty == nil {
 				return nil
 			}
-
-			order := []string{}
-			for name, ty := range comp.scope.dict {
-				if ty.Type() != comp.scope.previous.dict[name].Type() {
-					comp.throw(fmt.Errorf("recursive type definition"))
+			// The loop itself still finishes when its body always jumps.
+			if _, never := ty.(Never); never {
+				ty = Tuple{[]Type{}}
+			}
+			rest := comp.loop
+			exits := append([]exit{{span: span, block: comp.block, dict: comp.scope.dict}}, rest.continues...)
+			for _, exit := range exits {
+				if !comp.carry(exit, comp.scope.previous, condBlock) {
 					return nil
 				}
-				dest := comp.Duplicate(ty)
-				comp.Copy(ty, dest)
-				comp.scope.dict[name] = dest
-				order = append(order, name)
-			}
-			for _, name := range order {
-				comp.Copy(comp.scope.dict[name], comp.scope.previous.dict[name])
 			}
 
 			comp.block = exitBlock
 			comp.scope = comp.scope.previous
+			carried := comp.scope.dict
+			before := map[string]Type{}
+			for name := range carried {
+				before[name] = comp.scope.previous.get(name)
+			}
 			if !comp.mergeScopes(entryBlock, finalBlock) {
 				return nil
 			}
 			comp.block = exitBlock
 
-			bodyBlock.Jump(condBlock)
-			startBlock.Jump(condBlock)
 			finalBlock.Jump(exitBlock)
 
+			for _, exit := range first.breaks {
+				if !comp.joinExit(exit, before) {
+					return nil
+				}
+			}
+			for _, exit := range rest.breaks {
+				if !comp.joinExit(exit, carried) {
+					return nil
+				}
+			}
+			return ty
+
+		case syntax.Annotation:
+			ty := comp.compile(expr.Left)
+			if ty == nil {
+				return nil
+			}
+			expected := comp.annotatedType(expr.Right)
+			if expected == nil {
+				return nil
+			}
+			ty, _ = comp.conform(ty, expected)
 			return ty
 
 		case syntax.Func:
-			return &Func{expr.Left, expr.Right, comp.scope.newScope(), []Impl{}}
+			param, returns := expr.Left, syntax.Span{}
+			if arrow, ok := param.GetExpr().(syntax.Binary); ok && arrow.Op == syntax.Returns {
+				param, returns = arrow.Left, arrow.Right
+			}
+			params, ok := comp.annotatedParams(param)
+			if !ok {
+				return nil
+			}
+			env := comp.capture(expr.Right)
+			return Func{&function{param, returns, expr.Right, comp.scope.newScope(), []Impl{}, params}, env}
 
 		case syntax.Call:
+			if builtin := comp.builtin(expr.Left); builtin != nil {
+				return builtin(comp, expr.Right)
+			}
 			ty := comp.compile(expr.Left)
 			if ty == nil {
 				return nil
 			}
-			fn, ok := ty.(*Func)
+			if ref, ok := ty.(FuncRef); ok {
+				args := comp.compile(expr.Right)
+				if args == nil {
+					return nil
+				}
+				restore := comp.at(expr.Right)
+				args, ok = comp.conform(args, ref.sig.params)
+				restore()
+				if !ok {
+					return nil
+				}
+				return comp.callFuncRef(ref, args)
+			}
+			fn, ok := ty.(Func)
 			if !ok {
 				comp.throw(fmt.Errorf("expected a function in call"))
 				return nil
@@ -279,27 +509,32 @@ func (comp *compiler) compile(span syntax.Span) Type {
 			if args == nil {
 				return nil
 			}
+			if fn.params != nil {
+				restore := comp.at(expr.Right)
+				args, ok = comp.conform(args, fn.params)
+				restore()
+				if !ok {
+					return nil
+				}
+			}
 			for _, impl := range fn.impls {
 				if impl.params.Type() == args.Type() {
-					return comp.callFunction(impl, args)
+					return comp.callFunction(fn, impl, args)
 				}
 			}
-			entryBlock := comp.block
-			params := comp.Duplicate(args)
-			functionBlock := comp.program.NewBlock()
-			comp.block = functionBlock
-			oldScope := comp.scope
-			comp.scope = fn.scope
-			if !comp.match(fn.param, params) {
+			params := fn.params
+			if params == nil {
+				params = comp.Duplicate(args)
+			}
+			impl, ok := comp.compileImpl(fn, params)
+			if !ok {
 				return nil
 			}
-			returns := comp.compile(fn.body)
-			comp.block.Return()
-			impl := Impl{params, returns, comp.scope, functionBlock}
-			fn.impls = append(fn.impls, impl)
-			comp.scope = oldScope
-			comp.block = entryBlock
-			return comp.callFunction(impl, args)
+			return comp.callFunction(fn, impl, args)
+
+		case syntax.Arm:
+			comp.throw(fmt.Errorf("'->' can only be used in the arms of a match"))
+			return nil
 
 		default:
 			comp.throw(fmt.Errorf("invalid binary expression '%s'", expr.Op))
@@ -310,10 +545,140 @@ func (comp *compiler) compile(span syntax.Span) Type {
 	return nil
 }
 
-func (comp *compiler) callFunction(impl Impl, args Type) Type {
+// compileImpl compiles the body of fn for params. The Impl is registered
+// before the body is compiled so that recursive calls find it, which needs
+// the return type to be annotated when the body calls itself. The body works
+// on copies of params and the captured environment, as a recursive call
+// overwrites them with its own.
+func (comp *compiler) compileImpl(fn Func, params Type) (Impl, bool) {
+	var expected Type
+	if fn.returns.GetExpr() != nil {
+		if expected = comp.annotatedType(fn.returns); expected == nil {
+			return Impl{}, false
+		}
+	}
+	entryBlock := comp.block
+	functionBlock := comp.program.NewBlock()
+	index := len(fn.impls)
+	env := comp.Duplicate(fn.env)
+	fn.impls = append(fn.impls, Impl{params, env, expected, fn.scope, functionBlock})
+	comp.block = functionBlock
+	oldScope, oldLoop, oldReturns, oldArrays, oldEnvs, oldLater := comp.scope, comp.loop, comp.returns, comp.arrays, comp.envs, comp.later
+	comp.scope, comp.loop, comp.returns, comp.arrays, comp.envs, comp.later = fn.scope, nil, &[]exit{}, false, nil, nil
+	returns, ok := comp.compileBody(fn, params, env, expected)
+	comp.loop, comp.returns, comp.arrays, comp.envs, comp.later = oldLoop, oldReturns, oldArrays, oldEnvs, oldLater
+	if !ok {
+		fn.impls = append(fn.impls[:index], fn.impls[index+1:]...)
+		return Impl{}, false
+	}
+	comp.block.Return(ToValues(returns)...)
+	fn.impls[index].returns = returns
+	fn.impls[index].scope = comp.scope
+	comp.scope = oldScope
+	comp.block = entryBlock
+	return fn.impls[index], true
+}
+
+// boundName returns the name that statement binds when it is an assignment
+// to a variable, as in 'x = 1' or 'x: int = 1'.
+func boundName(statement syntax.Span) (string, bool) {
+	assign, ok := statement.GetExpr().(syntax.Binary)
+	if !ok || assign.Op != syntax.SingleEquals {
+		return "", false
+	}
+	target := assign.Left
+	if annotation, ok := target.GetExpr().(syntax.Binary); ok && annotation.Op == syntax.Annotation {
+		target = annotation.Left
+	}
+	ident, ok := target.GetExpr().(syntax.Identifier)
+	return ident.Ident, ok
+}
+
+// laterNames returns the names that the body of fn uses which are not bound
+// yet but are bound by a later statement of the current block, such as the
+// second of two mutually recursive functions.
+func (comp *compiler) laterNames(fn Func) map[string]struct{} {
+	names := map[string]struct{}{}
+	freeNames(fn.body, names)
+	for name := range names {
+		if comp.scope.get(name) != nil || comp.later[name] == 0 {
+			delete(names, name)
+		}
+	}
+	return names
+}
+
+// bound compiles the pending functions of the current scope that were only
+// waiting for name, unless a call has compiled them already.
+func (comp *compiler) bound(name string) bool {
+	ready := []Func{}
+	waiting := []pending{}
+	for _, p := range comp.pending {
+		if p.scope == comp.scope {
+			delete(p.names, name)
+		}
+		if len(p.names) == 0 {
+			ready = append(ready, p.fn)
+		} else {
+			waiting = append(waiting, p)
+		}
+	}
+	comp.pending = waiting
+	for _, fn := range ready {
+		if len(fn.impls) > 0 {
+			continue
+		}
+		if _, ok := comp.compileImpl(fn, fn.params); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (comp *compiler) compileBody(fn Func, params, env, expected Type) (Type, bool) {
+	captured := comp.Duplicate(env)
+	comp.Copy(env, captured)
+	for name, ty := range captured.(Struct).dict {
+		comp.scope.assign(name, ty)
+	}
+	locals := comp.Duplicate(params)
+	comp.Copy(params, locals)
+	if !comp.match(fn.param, locals) {
+		return nil, false
+	}
+	returns := comp.compile(fn.body)
+	if returns == nil {
+		return nil, false
+	}
+	exits := *comp.returns
+	if _, never := returns.(Never); !never {
+		exits = append(exits, exit{span: fn.body, block: comp.block, value: returns})
+	}
+	returns, ok := comp.joinReturns(exits, expected)
+	if ok && comp.arrays && holdsArray(returns) {
+		defer comp.at(fn.body)()
+		comp.throw(fmt.Errorf("cannot return '%s' from a function that creates arrays, since they are released when it returns", returns.Type()))
+		return nil, false
+	}
+	// A function value returned from here may be one created here, so the
+	// environments of those must outlive the frame.
+	if ok && holds(returns, isFuncRef) {
+		for _, env := range comp.envs {
+			env.block.MoveToHeap(env.addr)
+		}
+	}
+	return returns, ok
+}
+
+func (comp *compiler) callFunction(fn Func, impl Impl, args Type) Type {
+	if impl.returns == nil {
+		comp.throw(fmt.Errorf("recursive function needs a return type annotation"))
+		return nil
+	}
 	exitBlock := comp.program.NewBlock()
 	comp.Copy(args, impl.params)
-	comp.block.Call(impl.block, exitBlock)
+	comp.Copy(fn.env, impl.env)
+	comp.block.Call(impl.block, exitBlock, ToValues(impl.returns)...)
 	comp.block = exitBlock
 	returns := comp.Duplicate(impl.returns)
 	comp.Copy(impl.returns, returns)
@@ -321,7 +686,13 @@ func (comp *compiler) callFunction(impl Impl, args Type) Type {
 }
 
 func (comp *compiler) mergeScopes(block, condBlock *backend.Block) bool {
-	for name, newTy := range comp.scope.dict {
+	for name, def := range comp.scope.defs {
+		if _, ok := comp.scope.previous.getDef(name); !ok {
+			comp.scope.previous.defs[name] = def
+		}
+	}
+	for _, name := range sortedNames(comp.scope.dict) {
+		newTy := comp.scope.dict[name]
 		oldTy := comp.scope.previous.get(name)
 		if oldTy == nil {
 			comp.scope.previous.assign(name, comp.newMaybe(newTy, condBlock, block))
@@ -338,14 +709,50 @@ func (comp *compiler) mergeScopes(block, condBlock *backend.Block) bool {
 }
 
 func (comp *compiler) match(span syntax.Span, ty Type) bool {
+	defer comp.at(span)()
 	switch expr := span.GetExpr().(type) {
 
 	case syntax.Identifier:
+		if expr.Ident == "_" {
+			return true
+		}
+		if reg, ok := comp.scope.get(expr.Ident).(Mmio); ok {
+			if _, rebind := ty.(Mmio); !rebind {
+				comp.use(span, expr.Ident)
+				return comp.writeMmio(reg, ty)
+			}
+		}
+		if integer, ok := ty.(Integer); ok {
+			integer.literal = nil
+			ty = integer
+		}
+		comp.define(span, expr.Ident, ty)
 		comp.scope.assign(expr.Ident, ty)
-		return true
+		if fn, ok := ty.(Func); ok && fn.params != nil && len(fn.impls) == 0 {
+			if names := comp.laterNames(fn); len(names) > 0 {
+				comp.pending = append(comp.pending, pending{fn, comp.scope, names})
+			} else if _, ok := comp.compileImpl(fn, fn.params); !ok {
+				return false
+			}
+		}
+		return comp.bound(expr.Ident)
 
 	case syntax.Binary:
 		switch expr.Op {
+		case syntax.Index:
+			return comp.matchIndex(expr, ty)
+
+		case syntax.Annotation:
+			expected := comp.annotatedType(expr.Right)
+			if expected == nil {
+				return false
+			}
+			ty, ok := comp.conform(ty, expected)
+			if !ok {
+				return false
+			}
+			return comp.match(expr.Left, ty)
+
 		case syntax.Dot:
 			left := comp.compile(expr.Left)
 			if left == nil {
@@ -357,19 +764,26 @@ func (comp *compiler) match(span syntax.Span, ty Type) bool {
 				return false
 			}
 			comp.scope = comp.scope.newScope()
+			comp.scope.structure = true
 			for name, ty := range structure.dict {
 				comp.scope.dict[name] = ty
 			}
+			for name, def := range structure.defs {
+				comp.scope.defs[name] = def
+			}
 			if !comp.match(expr.Right, ty) {
 				return false
 			}
-			newStruct := Struct{comp.scope.dict}
+			newStruct := Struct{comp.scope.dict, comp.scope.defs}
 			comp.scope = comp.scope.previous
 			if !comp.match(expr.Left, newStruct) {
 				return false
 			}
 			return true
 
+		case syntax.Call:
+			return comp.matchPattern(span, ty)
+
 		}
 		comp.throw(fmt.Errorf("invalid binary expression '%s' to match against", expr.Op))
 		return false
@@ -390,6 +804,9 @@ func (comp *compiler) match(span syntax.Span, ty Type) bool {
 			}
 		}
 		return true
+
+	case syntax.Block, syntax.Unary, syntax.IntegerLiteral, syntax.CharLiteral, syntax.BooleanLiteral:
+		return comp.matchPattern(span, ty)
 	}
 	comp.throw(fmt.Errorf("invalid expression '%T' to match against", span.GetExpr()))
 	return false
@@ -408,6 +825,7 @@ func (comp *compiler) newMaybe(ty Type, someBlock, noneBlock *backend.Block) Typ
 }
 
 func (comp *compiler) compileBoolExpr(span syntax.Span, ifTrue, ifFalse *backend.Block) bool {
+	defer comp.at(span)()
 	switch expr := span.GetExpr().(type) {
 
 	case syntax.BooleanLiteral:
@@ -423,6 +841,7 @@ func (comp *compiler) compileBoolExpr(span syntax.Span, ifTrue, ifFalse *backend
 			comp.throw(fmt.Errorf("undefined variable '%s'", expr.Ident))
 			return false
 		}
+		comp.use(span, expr.Ident)
 		boolean, ok := ty.(Boolean)
 		if !ok {
 			comp.throw(fmt.Errorf("expected a boolean in variable '%s'", expr.Ident))
@@ -460,7 +879,15 @@ func (comp *compiler) compileBoolExpr(span syntax.Span, ifTrue, ifFalse *backend
 				comp.throw(fmt.Errorf("incompatiable types for addition"))
 				return false
 			}
-			comp.block.JumpIfGreater(rightInteger.val, leftInteger.val, ifTrue, ifFalse)
+			kind, ok := comp.unifyIntegers(leftInteger, rightInteger)
+			if !ok {
+				return false
+			}
+			if kind.width == backend.WordSize && !kind.signed {
+				comp.block.JumpIfAbove(rightInteger.val, leftInteger.val, ifTrue, ifFalse)
+			} else {
+				comp.block.JumpIfGreater(rightInteger.val, leftInteger.val, ifTrue, ifFalse)
+			}
 
 		}
 	default:
@@ -471,6 +898,22 @@ func (comp *compiler) compileBoolExpr(span syntax.Span, ifTrue, ifFalse *backend
 }
 
 func (comp *compiler) mergeTypes(a, b Type, aBlock, bBlock *backend.Block) Type {
+	// A path that ends in a jump never gets here, so the value from the
+	// other one is used as it is.
+	if _, ok := a.(Never); ok {
+		return b
+	}
+	if _, ok := b.(Never); ok {
+		return a
+	}
+
+	if isFunc(a) && isFunc(b) && !sameFunction(a, b) {
+		refs, ok := comp.unifyFuncs([]Type{a, b}, []*backend.Block{aBlock, bBlock})
+		if !ok {
+			return nil
+		}
+		a, b = refs[0], refs[1]
+	}
 
 	if a.Type() == b.Type() {
 		dest := comp.Duplicate(a)
@@ -486,7 +929,15 @@ func (comp *compiler) mergeTypes(a, b Type, aBlock, bBlock *backend.Block) Type
 
 	if aIs && bIs {
 		dict := make(map[string]Type)
-		for name, ty := range aStruct.dict {
+		defs := make(map[string]syntax.Span)
+		for name, def := range bStruct.defs {
+			defs[name] = def
+		}
+		for name, def := range aStruct.defs {
+			defs[name] = def
+		}
+		for _, name := range sortedNames(aStruct.dict) {
+			ty := aStruct.dict[name]
 			_, collision := bStruct.dict[name]
 			if collision {
 				dict[name] = ty
@@ -494,7 +945,8 @@ func (comp *compiler) mergeTypes(a, b Type, aBlock, bBlock *backend.Block) Type
 				dict[name] = comp.newMaybe(ty, aBlock, bBlock)
 			}
 		}
-		for name, ty := range bStruct.dict {
+		for _, name := range sortedNames(bStruct.dict) {
+			ty := bStruct.dict[name]
 			other, collision := dict[name]
 			if collision {
 				dict[name] = comp.mergeTypes(other, ty, aBlock, bBlock)
@@ -505,7 +957,7 @@ func (comp *compiler) mergeTypes(a, b Type, aBlock, bBlock *backend.Block) Type
 				dict[name] = comp.newMaybe(ty, bBlock, aBlock)
 			}
 		}
-		return Struct{dict}
+		return Struct{dict, defs}
 	}
 
 	aMaybe, aIs := a.(Maybe)
@@ -519,7 +971,7 @@ func (comp *compiler) mergeTypes(a, b Type, aBlock, bBlock *backend.Block) Type
 			aVal = aBlock.Constant(1)
 		}
 		bVal := bMaybe.val
-		if aIs {
+		if bIs {
 			b = bMaybe.ty
 		} else {
 			bVal = bBlock.Constant(1)
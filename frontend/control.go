@@ -0,0 +1,185 @@
+package frontend
+
+import (
+	"fmt"
+	"language/backend"
+	"language/syntax"
+)
+
+// exit is a jump out of the middle of a body by return, break or continue,
+// leaving from block. A return carries the value it returns, while break
+// and continue carry the variables assigned in the loop up to the jump,
+// which are reconciled with those on the other paths where they land.
+type exit struct {
+	span  syntax.Span
+	block *backend.Block
+	value Type
+	dict  map[string]Type
+}
+
+// loop collects the exits of the iteration of the innermost loop that is
+// being compiled, whose variables are assigned in scope.
+type loop struct {
+	scope     *scope
+	breaks    []exit
+	continues []exit
+}
+
+func (comp *compiler) compileReturn(expr syntax.Return) Type {
+	if comp.returns == nil {
+		comp.throw(fmt.Errorf("'return' can only be used in a function"))
+		return nil
+	}
+	value := comp.compile(expr.Value)
+	if value == nil {
+		return nil
+	}
+	*comp.returns = append(*comp.returns, exit{span: expr.Value, block: comp.block, value: value})
+	comp.block = comp.program.NewBlock()
+	return Never{}
+}
+
+// compileJump compiles break and continue. The code after them is never
+// run, so it goes in a block that nothing jumps to.
+func (comp *compiler) compileJump(span syntax.Span, keyword string) Type {
+	if comp.loop == nil {
+		comp.throw(fmt.Errorf("'%s' can only be used in a loop", keyword))
+		return nil
+	}
+	exit := exit{span: span, block: comp.block, dict: comp.assigned(comp.loop.scope)}
+	if keyword == "break" {
+		comp.loop.breaks = append(comp.loop.breaks, exit)
+	} else {
+		comp.loop.continues = append(comp.loop.continues, exit)
+	}
+	comp.block = comp.program.NewBlock()
+	return Never{}
+}
+
+// assigned returns the variables assigned from outer to the current scope,
+// as the enclosing scopes would see them once every scope in between was
+// merged into them.
+func (comp *compiler) assigned(outer *scope) map[string]Type {
+	scopes := []*scope{}
+	for s := comp.scope; s != outer.previous; s = s.previous {
+		scopes = append(scopes, s)
+	}
+	dict := map[string]Type{}
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if scopes[i].structure {
+			continue
+		}
+		for name, ty := range scopes[i].dict {
+			if _, ok := scopes[i].bound[name]; !ok {
+				dict[name] = ty
+			}
+		}
+	}
+	return dict
+}
+
+// joinReturns copies the values returned by exits into the values that the
+// function returns. They have the expected type when it is annotated, and
+// otherwise that of the first exit that is not an unsuffixed integer
+// literal, which takes on the kind of the others.
+func (comp *compiler) joinReturns(exits []exit, expected Type) (Type, bool) {
+	returns := expected
+	if returns == nil {
+		if len(exits) == 1 {
+			comp.block = exits[0].block
+			return exits[0].value, true
+		}
+		first := exits[0].value
+		for _, exit := range exits {
+			if integer, ok := exit.value.(Integer); !ok || integer.literal == nil {
+				first = exit.value
+				break
+			}
+		}
+		returns = comp.Duplicate(first)
+	}
+	var returnBlock *backend.Block
+	if len(exits) > 1 {
+		returnBlock = comp.program.NewBlock()
+	}
+	for _, exit := range exits {
+		comp.block = exit.block
+		restore := comp.at(exit.span)
+		value, ok := comp.conform(exit.value, returns)
+		restore()
+		if !ok {
+			return nil, false
+		}
+		comp.Copy(value, returns)
+		if returnBlock != nil {
+			comp.block.Jump(returnBlock)
+		}
+	}
+	if returnBlock != nil {
+		comp.block = returnBlock
+	}
+	return returns, true
+}
+
+// joinExit merges the variables along exit into those of comp.scope along
+// the path through comp.block, continuing in a new block where both paths
+// meet. base holds the value of every variable to merge from before exit
+// assigned it, which is nil for those that have no value yet.
+func (comp *compiler) joinExit(exit exit, base map[string]Type) bool {
+	block := comp.block
+	into := comp.scope
+	comp.scope = into.newScope()
+	unset := []string{}
+	for _, name := range sortedNames(base) {
+		ty, ok := exit.dict[name]
+		if !ok {
+			ty = base[name]
+		}
+		if ty == nil {
+			unset = append(unset, name)
+			continue
+		}
+		comp.scope.dict[name] = ty
+	}
+	if !comp.mergeScopes(block, exit.block) {
+		return false
+	}
+	for _, name := range unset {
+		into.assign(name, comp.newMaybe(into.get(name), block, exit.block))
+	}
+	joinBlock := comp.program.NewBlock()
+	block.Jump(joinBlock)
+	exit.block.Jump(joinBlock)
+	comp.block = joinBlock
+	return true
+}
+
+// carry copies the variables assigned along exit into those of loop, which
+// hold them from one iteration to the next, and jumps to condBlock. They are
+// copied through temporaries so that one assignment cannot overwrite a
+// value that another still reads.
+func (comp *compiler) carry(exit exit, loop *scope, condBlock *backend.Block) bool {
+	defer comp.at(exit.span)()
+	comp.block = exit.block
+	order := []string{}
+	temps := map[string]Type{}
+	for _, name := range sortedNames(exit.dict) {
+		ty := exit.dict[name]
+		previous, ok := loop.dict[name]
+		if maybe, isMaybe := previous.(Maybe); isMaybe && ty.Type() == maybe.ty.Type() {
+			ty = Maybe{comp.block.Constant(1), ty}
+		}
+		if !ok || ty.Type() != previous.Type() {
+			comp.throw(fmt.Errorf("recursive type definition"))
+			return false
+		}
+		temps[name] = comp.Duplicate(ty)
+		comp.Copy(ty, temps[name])
+		order = append(order, name)
+	}
+	for _, name := range order {
+		comp.Copy(temps[name], loop.dict[name])
+	}
+	comp.block.Jump(condBlock)
+	return true
+}
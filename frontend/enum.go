@@ -0,0 +1,174 @@
+package frontend
+
+import (
+	"fmt"
+	"language/backend"
+	"language/syntax"
+)
+
+// listed returns the items of a bracket that may be separated by commas,
+// newlines or both.
+func listed(span syntax.Span) []syntax.Span {
+	switch expr := span.GetExpr().(type) {
+	case syntax.Block:
+		items := []syntax.Span{}
+		for _, statement := range expr.Statements {
+			items = append(items, listed(statement)...)
+		}
+		return items
+	case syntax.Tuple:
+		return expr.Items
+	}
+	return []syntax.Span{span}
+}
+
+// splitVariant splits a variant written as Name or Name(payload) into its
+// name and the span of its payload, which is nil for a bare name.
+func splitVariant(span syntax.Span) (string, *syntax.Span, bool) {
+	switch expr := span.GetExpr().(type) {
+	case syntax.Identifier:
+		return expr.Ident, nil, true
+	case syntax.Binary:
+		if ident, ok := expr.Left.GetExpr().(syntax.Identifier); ok && expr.Op == syntax.Call {
+			return ident.Ident, &expr.Right, true
+		}
+	}
+	return "", nil, false
+}
+
+func (comp *compiler) compileEnum(expr syntax.Enum) Type {
+	variants := []variant{}
+	for _, span := range listed(expr.Block) {
+		variant, ok := comp.enumVariant(span, variants)
+		if !ok {
+			return nil
+		}
+		variants = append(variants, variant)
+	}
+	return EnumType{variants}
+}
+
+func (comp *compiler) enumVariant(span syntax.Span, variants []variant) (variant, bool) {
+	defer comp.at(span)()
+	name, payload, ok := splitVariant(span)
+	if !ok || name == "_" {
+		comp.throw(fmt.Errorf("expected a variant name optionally followed by the type of its payload"))
+		return variant{}, false
+	}
+	for _, other := range variants {
+		if other.name == name {
+			comp.throw(fmt.Errorf("variant '%s' is declared twice", name))
+			return variant{}, false
+		}
+	}
+	if payload == nil {
+		return variant{name, nil}, true
+	}
+	ty := comp.annotatedType(*payload)
+	return variant{name, ty}, ty != nil
+}
+
+func (ty EnumType) variant(name string) int {
+	for i, variant := range ty.variants {
+		if variant.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// instance builds a value of the enum with fresh values, for annotations.
+func (comp *compiler) instance(ty EnumType) Enum {
+	variants := make([]variant, len(ty.variants))
+	for i, variant := range ty.variants {
+		variants[i] = variant
+		if variant.payload != nil {
+			variants[i].payload = comp.Duplicate(variant.payload)
+		}
+	}
+	return Enum{comp.program.NewValue(), variants}
+}
+
+// compileVariant constructs the variant of enum named by span, which is
+// either a bare name or a call passing the payload.
+func (comp *compiler) compileVariant(enum EnumType, span syntax.Span) Type {
+	defer comp.at(span)()
+	name, args, ok := splitVariant(span)
+	if !ok {
+		comp.throw(fmt.Errorf("expected a variant of '%s' after '.'", Enum{nil, enum.variants}.Type()))
+		return nil
+	}
+	tag := enum.variant(name)
+	if tag < 0 {
+		comp.throw(fmt.Errorf("'%s' is not a variant of '%s'", name, Enum{nil, enum.variants}.Type()))
+		return nil
+	}
+	payload := enum.variants[tag].payload
+	if args == nil && payload != nil {
+		comp.throw(fmt.Errorf("variant '%s' needs a payload of type '%s'", name, payload.Type()))
+		return nil
+	}
+	if args != nil && payload == nil {
+		comp.throw(fmt.Errorf("variant '%s' has no payload", name))
+		return nil
+	}
+
+	// The payloads of the other variants are never read, so they all share
+	// one zero.
+	value := Enum{comp.block.Constant(tag), make([]variant, len(enum.variants))}
+	var zero *backend.Value
+	for i, variant := range enum.variants {
+		value.variants[i] = variant
+		if variant.payload == nil {
+			continue
+		}
+		if i != tag {
+			if zero == nil {
+				zero = comp.block.Constant(0)
+			}
+			vals := make([]*backend.Value, len(ToValues(variant.payload)))
+			for k := range vals {
+				vals[k] = zero
+			}
+			value.variants[i].payload = FromValues(vals, variant.payload)
+			continue
+		}
+		ty := comp.compile(*args)
+		if ty == nil {
+			return nil
+		}
+		restore := comp.at(*args)
+		ty, ok = comp.conform(ty, payload)
+		restore()
+		if !ok {
+			return nil
+		}
+		value.variants[i].payload = ty
+	}
+	return value
+}
+
+func (comp *compiler) writeEnum(ty Enum) {
+	exitBlock := comp.program.NewBlock()
+	for i, variant := range ty.variants {
+		nextBlock := exitBlock
+		if i < len(ty.variants)-1 {
+			variantBlock := comp.program.NewBlock()
+			nextBlock = comp.program.NewBlock()
+			comp.block.JumpIfEqual(ty.tag, comp.block.Constant(i), variantBlock, nextBlock)
+			comp.block = variantBlock
+		}
+		comp.block.WriteText(variant.name)
+		if variant.payload != nil {
+			if _, ok := variant.payload.(Tuple); ok {
+				comp.write(variant.payload)
+			} else {
+				comp.block.WriteText("(")
+				comp.write(variant.payload)
+				comp.block.WriteText(")")
+			}
+		}
+		comp.block.Jump(exitBlock)
+		comp.block = nextBlock
+	}
+}
@@ -0,0 +1,167 @@
+package frontend
+
+import (
+	"fmt"
+	"language/backend"
+)
+
+// signature is the calling convention of the FuncRefs of one type. Every
+// function converted to it gets a thunk that takes the arguments in params
+// and the address of its captured environment in env, calls the function
+// and leaves the result in returns.
+type signature struct {
+	params  Type
+	returns Type
+	env     *backend.Value
+	thunks  map[*function]*backend.Block
+	order   []*backend.Block
+	calls   []*backend.Block
+	// arrays is set once a function that captured an array is converted.
+	arrays bool
+}
+
+// funcRef converts fn into a function value that can be chosen at runtime,
+// storing its captured environment in the stack frame of the function that
+// converts it. The storage moves to the heap if that function turns out to
+// return a function value, which could be this one.
+func (comp *compiler) funcRef(fn Func) (FuncRef, bool) {
+	ok := fn.params != nil
+	if !ok {
+		comp.throw(fmt.Errorf("a function chosen at runtime needs annotated parameters"))
+		return FuncRef{}, false
+	}
+	var impl Impl
+	if len(fn.impls) > 0 {
+		impl = fn.impls[0]
+	} else if impl, ok = comp.compileImpl(fn, fn.params); !ok {
+		return FuncRef{}, false
+	}
+	if impl.returns == nil {
+		comp.throw(fmt.Errorf("recursive function needs a return type annotation"))
+		return FuncRef{}, false
+	}
+	sig := comp.signature(impl.params, impl.returns)
+	thunk, ok := sig.thunks[fn.function]
+	if !ok {
+		if thunk, ok = comp.compileThunk(fn, impl, sig); !ok {
+			return FuncRef{}, false
+		}
+	}
+	if holdsArray(fn.env) {
+		sig.arrays = true
+	}
+	code := comp.block.CodeAddress(thunk)
+	vals := ToValues(fn.env)
+	if len(vals) == 0 {
+		return FuncRef{code, comp.block.Constant(0), sig}, true
+	}
+	env := comp.block.Alloca(len(vals) * backend.WordSize)
+	comp.envs = append(comp.envs, storage{comp.block, env})
+	for i, val := range vals {
+		comp.block.Store(env, comp.block.Constant(i), val, backend.WordSize)
+	}
+	return FuncRef{code, env, sig}, true
+}
+
+func (comp *compiler) signature(params, returns Type) *signature {
+	key := FuncRef{sig: &signature{params: params, returns: returns}}.Type()
+	sig, ok := comp.signatures[key]
+	if !ok {
+		sig = &signature{
+			params:  comp.Duplicate(params),
+			returns: comp.Duplicate(returns),
+			env:     comp.program.NewValue(),
+			thunks:  map[*function]*backend.Block{},
+			order:   []*backend.Block{},
+			calls:   []*backend.Block{},
+		}
+		comp.signatures[key] = sig
+	}
+	return sig
+}
+
+// compileThunk compiles the function that an indirect call through sig runs
+// for fn, which loads the captured environment and calls impl.
+func (comp *compiler) compileThunk(fn Func, impl Impl, sig *signature) (*backend.Block, bool) {
+	entryBlock := comp.block
+	thunk := comp.program.NewBlock()
+	sig.thunks[fn.function] = thunk
+	sig.order = append(sig.order, thunk)
+	for _, call := range sig.calls {
+		call.AddCallTarget(thunk)
+	}
+	comp.block = thunk
+	vals := make([]*backend.Value, len(ToValues(fn.env)))
+	for i := range vals {
+		vals[i] = comp.block.Load(sig.env, comp.block.Constant(i), backend.WordSize)
+	}
+	env := FromValues(vals, fn.env).(Struct)
+	returns := comp.callFunction(Func{fn.function, env}, impl, sig.params)
+	if returns == nil {
+		return nil, false
+	}
+	comp.Copy(returns, sig.returns)
+	comp.block.Return(ToValues(sig.returns)...)
+	comp.block = entryBlock
+	return thunk, true
+}
+
+// callFuncRef calls the function that ref holds with args.
+func (comp *compiler) callFuncRef(ref FuncRef, args Type) Type {
+	exitBlock := comp.program.NewBlock()
+	comp.Copy(args, ref.sig.params)
+	comp.block.Copy(ref.env, ref.sig.env)
+	comp.block.CallIndirect(ref.code, exitBlock, ToValues(ref.sig.returns)...)
+	for _, thunk := range ref.sig.order {
+		comp.block.AddCallTarget(thunk)
+	}
+	ref.sig.calls = append(ref.sig.calls, comp.block)
+	comp.block = exitBlock
+	returns := comp.Duplicate(ref.sig.returns)
+	comp.Copy(ref.sig.returns, returns)
+	return returns
+}
+
+// unifyFuncs converts functions that may differ at runtime into FuncRefs of
+// one signature, emitting the conversion of each into the block it is
+// computed in.
+func (comp *compiler) unifyFuncs(tys []Type, blocks []*backend.Block) ([]Type, bool) {
+	entryBlock := comp.block
+	defer func() { comp.block = entryBlock }()
+	refs := make([]Type, len(tys))
+	for i, ty := range tys {
+		refs[i] = ty
+		if fn, ok := ty.(Func); ok {
+			comp.block = blocks[i]
+			if refs[i], ok = comp.funcRef(fn); !ok {
+				return nil, false
+			}
+		}
+		if refs[i].Type() != refs[0].Type() {
+			comp.throw(fmt.Errorf("incompatiable types, '%s' and '%s'", refs[0].Type(), refs[i].Type()))
+			return nil, false
+		}
+	}
+	return refs, true
+}
+
+func isFunc(ty Type) bool {
+	switch ty.(type) {
+	case Func, FuncRef:
+		return true
+	}
+	return false
+}
+
+func isFuncRef(ty Type) bool {
+	_, ok := ty.(FuncRef)
+	return ok
+}
+
+// sameFunction reports whether a and b are both created by the same fn
+// expression, so that they can be merged without choosing at runtime.
+func sameFunction(a, b Type) bool {
+	aFunc, aIs := a.(Func)
+	bFunc, bIs := b.(Func)
+	return aIs && bIs && aFunc.function == bFunc.function
+}
@@ -0,0 +1,65 @@
+package frontend
+
+import (
+	"fmt"
+	"language/backend"
+	"language/syntax"
+)
+
+type Info struct {
+	Types       []TypeInfo
+	Definitions []Definition
+	Symbols     []Symbol
+}
+
+type TypeInfo struct {
+	Span syntax.Span
+	Type Type
+}
+
+type Definition struct {
+	Use syntax.Span
+	Def syntax.Span
+}
+
+type SymbolKind string
+
+const (
+	VariableSymbol SymbolKind = "variable"
+	FieldSymbol    SymbolKind = "field"
+)
+
+type Symbol struct {
+	Name  string
+	Kind  SymbolKind
+	Span  syntax.Span
+	Range syntax.Span
+}
+
+type compileError struct {
+	span syntax.Span
+	err  error
+}
+
+func (err compileError) Error() string {
+	return fmt.Sprintf("(%d, %d) %s", err.span.Start().Line(), err.span.Start().Column(), err.err)
+}
+
+func (err compileError) Span() syntax.Span {
+	return err.span
+}
+
+// Analyze compiles span only to collect the types, definitions and symbols
+// of the program for tooling, along with any errors. The modules it imports
+// are compiled first as for Compile, but only span is described in the Info.
+func Analyze(span syntax.Span, modules ...Module) (*Info, []error) {
+	program := backend.NewProgram()
+	entry := program.NewBlock()
+	compiler := newCompiler(program, entry)
+	if _, ok := compiler.compileModules(modules); !ok {
+		return &Info{}, compiler.errors
+	}
+	compiler.info = &Info{}
+	compiler.compile(span)
+	return compiler.info, compiler.errors
+}
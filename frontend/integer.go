@@ -0,0 +1,129 @@
+package frontend
+
+import (
+	"fmt"
+	"language/backend"
+	"language/syntax"
+	"strconv"
+	"strings"
+)
+
+// IntKind is the width in bytes and signedness of an integer type. Arithmetic
+// wraps around on overflow in the virtual machine and native code alike.
+type IntKind struct {
+	name   string
+	width  int
+	signed bool
+}
+
+var intKind = IntKind{"int", 8, true}
+
+var intKinds = map[string]IntKind{
+	"int": intKind,
+	"u8":  {"u8", 1, false},
+	"i8":  {"i8", 1, true},
+	"u16": {"u16", 2, false},
+	"i16": {"i16", 2, true},
+	"u32": {"u32", 4, false},
+	"i32": {"i32", 4, true},
+	"u64": {"u64", 8, false},
+	"i64": {"i64", 8, true},
+}
+
+func (kind IntKind) fits(value uint64) bool {
+	if kind.signed {
+		return value <= uint64(1)<<(8*kind.width-1)-1
+	}
+	return kind.width == backend.WordSize || value < uint64(1)<<(8*kind.width)
+}
+
+// parseInteger splits an integer literal such as 0xffu8 into its value and
+// type suffix.
+func parseInteger(text string) (uint64, string, error) {
+	base, digits, set := 10, text, "0123456789"
+	if strings.HasPrefix(text, "0x") {
+		base, digits, set = 16, text[2:], "0123456789abcdefABCDEF"
+	}
+	end := strings.IndexFunc(digits, func(r rune) bool {
+		return !strings.ContainsRune(set, r)
+	})
+	if end < 0 {
+		end = len(digits)
+	}
+	value, err := strconv.ParseUint(digits[:end], base, 64)
+	return value, digits[end:], err
+}
+
+func (comp *compiler) compileIntegerLiteral(text string) Type {
+	value, suffix, err := parseInteger(text)
+	if err != nil {
+		comp.throw(fmt.Errorf("integer literal '%s' is out of range", text))
+		return nil
+	}
+	integer := Integer{kind: intKind, literal: &value}
+	if suffix != "" {
+		kind, ok := intKinds[suffix]
+		if !ok {
+			comp.throw(fmt.Errorf("unknown integer type suffix '%s'", suffix))
+			return nil
+		}
+		integer = Integer{kind: kind}
+	}
+	if !integer.kind.fits(value) {
+		comp.throw(fmt.Errorf("integer literal '%s' does not fit in '%s'", text, integer.kind.name))
+		return nil
+	}
+	integer.val = comp.block.Constant(int(value))
+	return integer
+}
+
+// coerceInteger gives integer the given kind, which an unsuffixed literal
+// takes on when its value fits.
+func (comp *compiler) coerceInteger(integer Integer, kind IntKind) (Integer, bool) {
+	switch {
+	case integer.kind == kind:
+		return integer, true
+	case integer.literal != nil && kind.fits(*integer.literal):
+		return Integer{integer.val, kind, nil}, true
+	case integer.literal != nil:
+		comp.throw(fmt.Errorf("integer literal %d does not fit in '%s'", *integer.literal, kind.name))
+		return Integer{}, false
+	}
+	comp.throw(fmt.Errorf("mismatched integer types '%s' and '%s'", integer.kind.name, kind.name))
+	return Integer{}, false
+}
+
+// unifyIntegers finds the kind shared by the operands of a binary operation.
+func (comp *compiler) unifyIntegers(left, right Integer) (IntKind, bool) {
+	if left.literal != nil && right.literal == nil {
+		left, right = right, left
+	}
+	right, ok := comp.coerceInteger(right, left.kind)
+	return right.kind, ok
+}
+
+func (comp *compiler) addIntegers(left, right Integer) Type {
+	kind, ok := comp.unifyIntegers(left, right)
+	if !ok {
+		return nil
+	}
+	if kind.width == backend.WordSize {
+		return Integer{comp.block.Add(left.val, right.val), kind, nil}
+	}
+	return Integer{comp.block.SizedBinary(left.val, right.val, backend.Add, kind.width, kind.signed), kind, nil}
+}
+
+// convertInteger returns the builtin conversion to kind, which wraps values
+// that do not fit.
+func convertInteger(kind IntKind) func(comp *compiler, args syntax.Span) Type {
+	return func(comp *compiler, args syntax.Span) Type {
+		integer, ok := comp.compileInteger(args, kind.name)
+		if !ok {
+			return nil
+		}
+		if kind.width == backend.WordSize {
+			return Integer{integer.val, kind, nil}
+		}
+		return Integer{comp.block.Extend(integer.val, kind.width, kind.signed), kind, nil}
+	}
+}
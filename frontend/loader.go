@@ -0,0 +1,87 @@
+package frontend
+
+import (
+	"fmt"
+	"io/ioutil"
+	"language/syntax"
+	"path/filepath"
+	"strings"
+)
+
+// moduleExtension is appended to the path of an import to find its file, so
+// `import "lib/math"` loads lib/math.txt below the root.
+const moduleExtension = ".txt"
+
+// loader reads the modules imported by a program from files below root.
+type loader struct {
+	root    string
+	modules []Module
+	loaded  map[string]bool
+	stack   []string
+	errors  []error
+}
+
+// LoadModules parses every module that ast imports, directly or through
+// other modules, from files below root. They are returned in an order where
+// each module comes after the modules it imports, ready for Compile. An
+// import cycle is reported at the import that closes it.
+func LoadModules(root string, ast syntax.Span) ([]Module, []error) {
+	loader := &loader{root: root, loaded: map[string]bool{}}
+	loader.imports("", ast)
+	return loader.modules, loader.errors
+}
+
+// imports loads the modules imported by the module at path, which is empty
+// for the program itself.
+func (loader *loader) imports(path string, ast syntax.Span) {
+	for _, span := range syntax.Imports(ast) {
+		imported, err := span.GetExpr().(syntax.Import).Module()
+		if err != nil {
+			loader.throw(path, span, err)
+			continue
+		}
+		loader.load(path, span, imported)
+	}
+}
+
+func (loader *loader) load(importer string, span syntax.Span, path string) {
+	for i, active := range loader.stack {
+		if active == path {
+			cycle := append(loader.stack[i:], path)
+			loader.throw(importer, span, fmt.Errorf("import cycle %s", strings.Join(cycle, " -> ")))
+			return
+		}
+	}
+	if loader.loaded[path] {
+		return
+	}
+	loader.loaded[path] = true
+
+	data, err := ioutil.ReadFile(filepath.Join(loader.root, filepath.FromSlash(path)+moduleExtension))
+	if err != nil {
+		loader.throw(importer, span, fmt.Errorf("cannot import '%s': %v", path, err))
+		return
+	}
+	ast, errs := syntax.Parse(string(data))
+	if len(errs) != 0 {
+		for _, err := range errs {
+			loader.errors = append(loader.errors, moduleError{path, err})
+		}
+		return
+	}
+
+	loader.stack = append(loader.stack, path)
+	loader.imports(path, ast)
+	loader.stack = loader.stack[:len(loader.stack)-1]
+	loader.modules = append(loader.modules, Module{Path: path, Ast: ast})
+}
+
+// throw reports err at the import span in the module at path, leaving out
+// the path for the program itself like the errors of the compiler.
+func (loader *loader) throw(path string, span syntax.Span, err error) {
+	err = compileError{span, err}
+	if path != "" {
+		err = moduleError{path, err}
+	}
+	loader.errors = append(loader.errors, err)
+}
@@ -0,0 +1,51 @@
+package frontend
+
+import (
+	"fmt"
+	"language/syntax"
+)
+
+func (comp *compiler) compileMmio(expr syntax.Mmio) Type {
+	kind, ok := intKinds[expr.Type]
+	if !ok || expr.Type == "int" {
+		comp.throw(fmt.Errorf("unknown register type '%s'", expr.Type))
+		return nil
+	}
+	if kind.signed {
+		comp.throw(fmt.Errorf("register type '%s' must be unsigned", expr.Type))
+		return nil
+	}
+	literal, ok := expr.Address.GetExpr().(syntax.IntegerLiteral)
+	if !ok {
+		comp.throw(fmt.Errorf("the address of a register must be an integer literal"))
+		return nil
+	}
+	address, _, err := parseInteger(literal.Value)
+	if err != nil {
+		comp.throw(fmt.Errorf("register address '%s' is out of range", literal.Value))
+		return nil
+	}
+	if address%uint64(kind.width) != 0 {
+		comp.throw(fmt.Errorf("address 0x%x is not aligned to the %d bytes of '%s'", address, kind.width, expr.Type))
+		return nil
+	}
+	return Mmio{int(address), kind}
+}
+
+func (comp *compiler) readMmio(reg Mmio) Integer {
+	val := comp.block.VolatileLoad(comp.block.Constant(reg.address), comp.block.Constant(0), reg.kind.width)
+	return Integer{val, reg.kind, nil}
+}
+
+func (comp *compiler) writeMmio(reg Mmio, ty Type) bool {
+	integer, ok := ty.(Integer)
+	if !ok {
+		comp.throw(fmt.Errorf("cannot write '%s' to register '%s'", ty.Type(), reg.Type()))
+		return false
+	}
+	if integer, ok = comp.coerceInteger(integer, reg.kind); !ok {
+		return false
+	}
+	comp.block.VolatileStore(comp.block.Constant(reg.address), comp.block.Constant(0), integer.val, reg.kind.width)
+	return true
+}
@@ -0,0 +1,74 @@
+package frontend
+
+import (
+	"fmt"
+	"language/syntax"
+)
+
+// Module is a parsed source file that other modules import by Path, as
+// returned by syntax.Import.Module.
+type Module struct {
+	Path string
+	Ast  syntax.Span
+}
+
+type moduleError struct {
+	path string
+	err  error
+}
+
+func (err moduleError) Error() string {
+	return fmt.Sprintf("%s: %s", err.path, err.err)
+}
+
+// compileModule compiles the top level of module in a scope of its own, so
+// that it cannot see the variables of the modules that import it, and
+// records the variables it defines as the namespace that imports of it
+// evaluate to. Errors in the module are reported with its path.
+func (comp *compiler) compileModule(module Module) bool {
+	errors := len(comp.errors)
+	outer := comp.scope
+	comp.scope = newScope()
+	comp.scope.structure = true
+	ty := comp.compile(module.Ast)
+	namespace := Struct{comp.scope.dict, comp.scope.defs}
+	comp.scope = outer
+	for i := errors; i < len(comp.errors); i++ {
+		comp.errors[i] = moduleError{module.Path, comp.errors[i]}
+	}
+	if ty == nil {
+		return false
+	}
+	comp.modules[module.Path] = namespace
+	return true
+}
+
+// compileModules compiles the modules that are not compiled yet in order,
+// returning the paths of those it compiled.
+func (comp *compiler) compileModules(modules []Module) ([]string, bool) {
+	compiled := []string{}
+	for _, module := range modules {
+		if _, ok := comp.modules[module.Path]; ok {
+			continue
+		}
+		if !comp.compileModule(module) {
+			return compiled, false
+		}
+		compiled = append(compiled, module.Path)
+	}
+	return compiled, true
+}
+
+func (comp *compiler) compileImport(expr syntax.Import) Type {
+	path, err := expr.Module()
+	if err != nil {
+		comp.throw(err)
+		return nil
+	}
+	namespace, ok := comp.modules[path]
+	if !ok {
+		comp.throw(fmt.Errorf("module '%s' is not loaded", path))
+		return nil
+	}
+	return namespace
+}
@@ -0,0 +1,534 @@
+package frontend
+
+import (
+	"fmt"
+	"language/backend"
+	"language/syntax"
+	"strconv"
+	"strings"
+)
+
+// pattern is a pattern checked against ty, the value it is matched with.
+// ctor is the constructor it tests for: the name of a variant, "true",
+// "false", "some", "none" or the value of an integer literal, or "tuple" and
+// "struct", which every value of their type has. The patterns of the parts
+// of the value are in args. A pattern without a ctor matches every value
+// and binds it to the name in span when bind is set.
+type pattern struct {
+	span syntax.Span
+	ty   Type
+	ctor string
+	args []*pattern
+	bind bool
+}
+
+// arm is one arm of a match, which evaluates body when pattern matches.
+type arm struct {
+	span    syntax.Span
+	pattern *pattern
+	body    syntax.Span
+}
+
+// pattern checks that span is a valid pattern for values of ty.
+func (comp *compiler) pattern(span syntax.Span, ty Type) (*pattern, bool) {
+	defer comp.at(span)()
+	p := &pattern{span: span, ty: ty}
+	mismatch := func() (*pattern, bool) {
+		comp.throw(fmt.Errorf("pattern cannot match a value of type '%s'", ty.Type()))
+		return nil, false
+	}
+	switch expr := span.GetExpr().(type) {
+	case syntax.Identifier:
+		if expr.Ident == "_" {
+			return p, true
+		}
+		if _, ok := ty.(Maybe); ok && expr.Ident == "none" {
+			p.ctor = "none"
+			return p, true
+		}
+		if enum, ok := ty.(Enum); ok && (EnumType{enum.variants}).variant(expr.Ident) >= 0 {
+			return comp.variantPattern(p, enum, expr.Ident, nil)
+		}
+		p.bind = true
+		return p, true
+
+	case syntax.IntegerLiteral:
+		integer, ok := ty.(Integer)
+		if !ok {
+			return mismatch()
+		}
+		value, suffix, err := parseInteger(expr.Value)
+		if err != nil {
+			comp.throw(fmt.Errorf("integer literal '%s' is out of range", expr.Value))
+			return nil, false
+		}
+		if suffix != "" && suffix != integer.kind.name {
+			comp.throw(fmt.Errorf("mismatched integer types '%s' and '%s'", integer.kind.name, suffix))
+			return nil, false
+		}
+		return comp.integerPattern(p, integer, value, expr.Value)
+
+	case syntax.CharLiteral:
+		integer, ok := ty.(Integer)
+		if !ok {
+			return mismatch()
+		}
+		value, _ := syntax.Unquote(expr.Value)
+		return comp.integerPattern(p, integer, uint64(value[0]), expr.Value)
+
+	case syntax.BooleanLiteral:
+		if _, ok := ty.(Boolean); !ok {
+			return mismatch()
+		}
+		p.ctor = expr.Value
+		return p, true
+
+	case syntax.Unary:
+		maybe, ok := ty.(Maybe)
+		if expr.Op != syntax.Maybe || !ok {
+			return mismatch()
+		}
+		inner, ok := comp.pattern(expr.Expr, maybe.ty)
+		if !ok {
+			return nil, false
+		}
+		p.ctor, p.args = "some", []*pattern{inner}
+		return p, true
+
+	case syntax.Tuple:
+		tuple, ok := ty.(Tuple)
+		if !ok || len(tuple.items) != len(expr.Items) {
+			return mismatch()
+		}
+		p.ctor = "tuple"
+		for i, item := range expr.Items {
+			arg, ok := comp.pattern(item, tuple.items[i])
+			if !ok {
+				return nil, false
+			}
+			p.args = append(p.args, arg)
+		}
+		return p, true
+
+	case syntax.Block:
+		structure, ok := ty.(Struct)
+		if !ok {
+			return mismatch()
+		}
+		return comp.structPattern(p, structure, listed(span))
+
+	case syntax.Binary:
+		switch expr.Op {
+		case syntax.Call:
+			ident, isIdent := expr.Left.GetExpr().(syntax.Identifier)
+			enum, isEnum := ty.(Enum)
+			if !isIdent || !isEnum {
+				return mismatch()
+			}
+			return comp.variantPattern(p, enum, ident.Ident, &expr.Right)
+
+		case syntax.Annotation:
+			expected := comp.annotatedType(expr.Right)
+			if expected == nil {
+				return nil, false
+			}
+			if expected.Type() != ty.Type() {
+				comp.throw(fmt.Errorf("expected '%s' instead of '%s'", expected.Type(), ty.Type()))
+				return nil, false
+			}
+			return comp.pattern(expr.Left, ty)
+		}
+	}
+	comp.throw(fmt.Errorf("invalid pattern"))
+	return nil, false
+}
+
+func (comp *compiler) integerPattern(p *pattern, integer Integer, value uint64, text string) (*pattern, bool) {
+	if !integer.kind.fits(value) {
+		comp.throw(fmt.Errorf("integer literal '%s' does not fit in '%s'", text, integer.kind.name))
+		return nil, false
+	}
+	p.ctor = strconv.FormatUint(value, 10)
+	return p, true
+}
+
+func (comp *compiler) variantPattern(p *pattern, enum Enum, name string, payload *syntax.Span) (*pattern, bool) {
+	tag := EnumType{enum.variants}.variant(name)
+	if tag < 0 {
+		comp.throw(fmt.Errorf("'%s' is not a variant of '%s'", name, enum.Type()))
+		return nil, false
+	}
+	p.ctor = name
+	ty := enum.variants[tag].payload
+	switch {
+	case payload != nil && ty == nil:
+		comp.throw(fmt.Errorf("variant '%s' has no payload", name))
+		return nil, false
+	case payload != nil:
+		arg, ok := comp.pattern(*payload, ty)
+		if !ok {
+			return nil, false
+		}
+		p.args = []*pattern{arg}
+	case ty != nil:
+		p.args = []*pattern{{ty: ty}}
+	}
+	return p, true
+}
+
+// structPattern matches the fields named by items, each either a name that
+// the field is bound to or a name followed by ':' and a pattern for the
+// field. Fields that are left out match any value.
+func (comp *compiler) structPattern(p *pattern, structure Struct, items []syntax.Span) (*pattern, bool) {
+	fields := map[string]*pattern{}
+	for _, item := range items {
+		name, sub := "", item
+		switch expr := item.GetExpr().(type) {
+		case syntax.Identifier:
+			name = expr.Ident
+		case syntax.Binary:
+			if ident, ok := expr.Left.GetExpr().(syntax.Identifier); ok && expr.Op == syntax.Annotation {
+				name, sub = ident.Ident, expr.Right
+			}
+		}
+		restore := comp.at(item)
+		field, ok := structure.dict[name]
+		switch {
+		case name == "":
+			comp.throw(fmt.Errorf("expected a field name optionally followed by ':' and a pattern"))
+		case !ok:
+			comp.throw(fmt.Errorf("'%s' is not a field of '%s'", name, structure.Type()))
+		case fields[name] != nil:
+			comp.throw(fmt.Errorf("field '%s' is matched twice", name))
+		}
+		restore()
+		if name == "" || !ok || fields[name] != nil {
+			return nil, false
+		}
+		if fields[name], ok = comp.pattern(sub, field); !ok {
+			return nil, false
+		}
+	}
+	p.ctor = "struct"
+	for _, name := range sortedNames(structure.dict) {
+		field, ok := fields[name]
+		if !ok {
+			field = &pattern{ty: structure.dict[name]}
+		}
+		p.args = append(p.args, field)
+	}
+	return p, true
+}
+
+// constructors lists every constructor of values of ty, or nothing when
+// there are too many to list.
+func constructors(ty Type) []string {
+	switch ty := ty.(type) {
+	case Enum:
+		ctors := make([]string, len(ty.variants))
+		for i, variant := range ty.variants {
+			ctors[i] = variant.name
+		}
+		return ctors
+	case Boolean:
+		return []string{"true", "false"}
+	case Maybe:
+		return []string{"some", "none"}
+	case Tuple:
+		return []string{"tuple"}
+	case Struct:
+		return []string{"struct"}
+	}
+	return nil
+}
+
+// argTypes returns the types of the parts of a value of ty made by ctor.
+func argTypes(ty Type, ctor string) []Type {
+	switch ty := ty.(type) {
+	case Enum:
+		if payload := ty.variants[EnumType{ty.variants}.variant(ctor)].payload; payload != nil {
+			return []Type{payload}
+		}
+	case Maybe:
+		if ctor == "some" {
+			return []Type{ty.ty}
+		}
+	case Tuple:
+		return append([]Type{}, ty.items...)
+	case Struct:
+		tys := []Type{}
+		for _, name := range sortedNames(ty.dict) {
+			tys = append(tys, ty.dict[name])
+		}
+		return tys
+	}
+	return []Type{}
+}
+
+func wildcards(tys []Type) []*pattern {
+	row := make([]*pattern, len(tys))
+	for i, ty := range tys {
+		row[i] = &pattern{ty: ty}
+	}
+	return row
+}
+
+// specialize keeps the rows whose first pattern can match a value made by
+// ctor, replacing that pattern with the patterns of the parts of the value.
+func specialize(rows [][]*pattern, ctor string, ty Type) [][]*pattern {
+	specialized := [][]*pattern{}
+	for _, row := range rows {
+		switch row[0].ctor {
+		case ctor:
+			specialized = append(specialized, append(append([]*pattern{}, row[0].args...), row[1:]...))
+		case "":
+			specialized = append(specialized, append(wildcards(argTypes(ty, ctor)), row[1:]...))
+		}
+	}
+	return specialized
+}
+
+// useful reports whether row matches some values of the types tys that none
+// of rows match, which is how arms that can never be taken and matches that
+// are not exhaustive are found.
+func useful(rows [][]*pattern, row []*pattern, tys []Type) bool {
+	if len(row) == 0 {
+		return len(rows) == 0
+	}
+	if ctor := row[0].ctor; ctor != "" {
+		args := append(append([]*pattern{}, row[0].args...), row[1:]...)
+		return useful(specialize(rows, ctor, tys[0]), args, append(argTypes(tys[0], ctor), tys[1:]...))
+	}
+	ctors := constructors(tys[0])
+	complete := len(ctors) > 0
+	for _, ctor := range ctors {
+		used := false
+		for _, other := range rows {
+			used = used || other[0].ctor == ctor
+		}
+		complete = complete && used
+	}
+	if complete {
+		for _, ctor := range ctors {
+			args := argTypes(tys[0], ctor)
+			if useful(specialize(rows, ctor, tys[0]), append(wildcards(args), row[1:]...), append(args, tys[1:]...)) {
+				return true
+			}
+		}
+		return false
+	}
+	defaults := [][]*pattern{}
+	for _, other := range rows {
+		if other[0].ctor == "" {
+			defaults = append(defaults, other[1:])
+		}
+	}
+	return useful(defaults, row[1:], tys[1:])
+}
+
+// missing describes the constructors of ty that rows do not cover.
+func missing(rows [][]*pattern, ty Type) string {
+	names := []string{}
+	for _, ctor := range constructors(ty) {
+		args := argTypes(ty, ctor)
+		if !useful(specialize(rows, ctor, ty), wildcards(args), args) {
+			continue
+		}
+		switch {
+		case ctor == "some":
+			ctor = "_?"
+		case ctor == "tuple" || ctor == "struct":
+			return ""
+		case len(args) > 0:
+			ctor += "(_)"
+		}
+		names = append(names, "'"+ctor+"'")
+	}
+	return strings.Join(names, ", ")
+}
+
+func refutable(p *pattern) bool {
+	return useful([][]*pattern{{p}}, []*pattern{{ty: p.ty}}, []Type{p.ty})
+}
+
+// test emits the checks that the value of p matches it, continuing in a new
+// block when it does and jumping to fail when it does not.
+func (comp *compiler) test(p *pattern, fail *backend.Block) {
+	check := func(val *backend.Value, constant int) {
+		next := comp.program.NewBlock()
+		comp.block.JumpIfEqual(val, comp.block.Constant(constant), next, fail)
+		comp.block = next
+	}
+	switch ty := p.ty.(type) {
+	case Integer:
+		if p.ctor != "" {
+			value, _ := strconv.ParseUint(p.ctor, 10, 64)
+			check(ty.val, int(value))
+		}
+	case Boolean:
+		switch p.ctor {
+		case "true":
+			check(ty.val, 1)
+		case "false":
+			check(ty.val, 0)
+		}
+	case Maybe:
+		switch p.ctor {
+		case "some":
+			check(ty.val, 1)
+		case "none":
+			check(ty.val, 0)
+		}
+	case Enum:
+		if p.ctor != "" {
+			check(ty.tag, EnumType{ty.variants}.variant(p.ctor))
+		}
+	}
+	for _, arg := range p.args {
+		comp.test(arg, fail)
+	}
+}
+
+// bind assigns the parts of the value of p to the names in it.
+func (comp *compiler) bind(p *pattern, names map[string]struct{}) bool {
+	if p.bind {
+		names[p.span.GetExpr().(syntax.Identifier).Ident] = struct{}{}
+		return comp.match(p.span, p.ty)
+	}
+	for _, arg := range p.args {
+		if !comp.bind(arg, names) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPattern binds a pattern in an assignment or the parameters of a
+// function, where a pattern that can fail to match is an error.
+func (comp *compiler) matchPattern(span syntax.Span, ty Type) bool {
+	p, ok := comp.pattern(span, ty)
+	if !ok {
+		return false
+	}
+	if refutable(p) {
+		comp.throw(fmt.Errorf("pattern can fail to match '%s', use a match instead", ty.Type()))
+		return false
+	}
+	return comp.bind(p, map[string]struct{}{})
+}
+
+func (comp *compiler) compileMatch(expr syntax.Match) Type {
+	subject := comp.compile(expr.Value)
+	if subject == nil {
+		return nil
+	}
+	arms := []arm{}
+	rows := [][]*pattern{}
+	for _, span := range listed(expr.Block) {
+		arm, ok := comp.matchArm(span, subject, rows)
+		if !ok {
+			return nil
+		}
+		arms = append(arms, arm)
+		rows = append(rows, []*pattern{arm.pattern})
+	}
+	if useful(rows, []*pattern{{ty: subject}}, []Type{subject}) {
+		if names := missing(rows, subject); names != "" {
+			comp.throw(fmt.Errorf("match is not exhaustive, missing %s", names))
+		} else {
+			comp.throw(fmt.Errorf("match is not exhaustive, add a '_' arm"))
+		}
+		return nil
+	}
+	return comp.compileArms(arms)
+}
+
+func (comp *compiler) matchArm(span syntax.Span, subject Type, rows [][]*pattern) (arm, bool) {
+	defer comp.at(span)()
+	expr, ok := span.GetExpr().(syntax.Binary)
+	if !ok || expr.Op != syntax.Arm {
+		comp.throw(fmt.Errorf("expected an arm 'pattern -> value' in match"))
+		return arm{}, false
+	}
+	p, ok := comp.pattern(expr.Left, subject)
+	if !ok {
+		return arm{}, false
+	}
+	if !useful(rows, []*pattern{p}, []Type{subject}) {
+		comp.throw(fmt.Errorf("unreachable arm, the arms before it match every value it does"))
+		return arm{}, false
+	}
+	return arm{span, p, expr.Right}, true
+}
+
+// compileArms lowers arms to a chain of tests, the last arm being taken
+// without one since the arms are exhaustive.
+func (comp *compiler) compileArms(arms []arm) Type {
+	if len(arms) == 1 {
+		return comp.compileArm(arms[0])
+	}
+	restBlock := comp.program.NewBlock()
+	exitBlock := comp.program.NewBlock()
+	comp.test(arms[0].pattern, restBlock)
+
+	comp.scope = comp.scope.newScope()
+	ty := comp.compileArm(arms[0])
+	if ty == nil {
+		return nil
+	}
+	armBlock := comp.block
+	if !comp.mergeScopes(restBlock, armBlock) {
+		return nil
+	}
+
+	comp.block = restBlock
+	comp.scope = comp.scope.newScope()
+	rest := comp.compileArms(arms[1:])
+	if rest == nil {
+		return nil
+	}
+	restBlock = comp.block
+	if !comp.mergeScopes(armBlock, restBlock) {
+		return nil
+	}
+	ty = comp.mergeTypes(ty, rest, armBlock, restBlock)
+	if ty == nil {
+		return nil
+	}
+	armBlock.Jump(exitBlock)
+	restBlock.Jump(exitBlock)
+	comp.block = exitBlock
+	return ty
+}
+
+// compileArm binds the names in the pattern of arm and compiles its value.
+// The bound names are only visible in the arm, while other variables it
+// assigns stay assigned after it.
+func (comp *compiler) compileArm(arm arm) Type {
+	defer comp.at(arm.span)()
+	comp.scope = comp.scope.newScope()
+	bound := map[string]struct{}{}
+	comp.scope.bound = bound
+	if !comp.bind(arm.pattern, bound) {
+		return nil
+	}
+	ty := comp.compile(arm.body)
+	if ty == nil {
+		return nil
+	}
+	inner := comp.scope
+	comp.scope = inner.previous
+	for name := range bound {
+		delete(inner.dict, name)
+		delete(inner.defs, name)
+	}
+	for name, def := range inner.defs {
+		if _, ok := comp.scope.getDef(name); !ok {
+			comp.scope.defs[name] = def
+		}
+	}
+	for name, ty := range inner.dict {
+		comp.scope.assign(name, ty)
+	}
+	return ty
+}
@@ -0,0 +1,100 @@
+package frontend
+
+import (
+	"language/backend"
+	"sort"
+)
+
+// Print appends code to block that writes the value of ty followed by a
+// newline, returning the block that execution continues from.
+func Print(program *backend.Program, block *backend.Block, ty Type) *backend.Block {
+	comp := &compiler{program: program, block: block}
+	comp.write(ty)
+	comp.block.WriteText("\n")
+	return comp.block
+}
+
+func (comp *compiler) writeChoice(tag *backend.Value, some func(), none func()) {
+	someBlock := comp.program.NewBlock()
+	noneBlock := comp.program.NewBlock()
+	exitBlock := comp.program.NewBlock()
+	comp.block.JumpIfEqual(tag, comp.block.Constant(1), someBlock, noneBlock)
+	comp.block = someBlock
+	some()
+	comp.block.Jump(exitBlock)
+	comp.block = noneBlock
+	none()
+	comp.block.Jump(exitBlock)
+	comp.block = exitBlock
+}
+
+func (comp *compiler) write(ty Type) {
+	switch ty := ty.(type) {
+	case Integer:
+		if ty.kind.width == backend.WordSize && !ty.kind.signed {
+			comp.block.WriteUint(ty.val)
+		} else {
+			comp.block.WriteInt(ty.val)
+		}
+
+	case Boolean:
+		comp.writeChoice(ty.val, func() {
+			comp.block.WriteText("true")
+		}, func() {
+			comp.block.WriteText("false")
+		})
+
+	case String:
+		comp.block.Write(ty.addr, ty.length)
+
+	case Maybe:
+		comp.writeChoice(ty.val, func() {
+			comp.write(ty.ty)
+		}, func() {
+			comp.block.WriteText("none")
+		})
+
+	case Struct:
+		order := []string{}
+		for name := range ty.dict {
+			order = append(order, name)
+		}
+		sort.Strings(order)
+		comp.block.WriteText("{")
+		for i, name := range order {
+			if i > 0 {
+				comp.block.WriteText(", ")
+			}
+			comp.block.WriteText(name + ": ")
+			comp.write(ty.dict[name])
+		}
+		comp.block.WriteText("}")
+
+	case Tuple:
+		comp.block.WriteText("(")
+		for i, item := range ty.items {
+			if i > 0 {
+				comp.block.WriteText(", ")
+			}
+			comp.write(item)
+		}
+		comp.block.WriteText(")")
+
+	case Enum:
+		comp.writeEnum(ty)
+
+	case Array:
+		words := len(ToValues(ty.elem))
+		comp.block.WriteText("[")
+		for i := 0; i < ty.length; i++ {
+			if i > 0 {
+				comp.block.WriteText(", ")
+			}
+			comp.write(comp.loadElement(ty, comp.block.Constant(i*words)))
+		}
+		comp.block.WriteText("]")
+
+	default:
+		comp.block.WriteText(ty.Type())
+	}
+}
@@ -1,30 +1,63 @@
-package frontend
-
-type scope struct {
-	dict     map[string]Type
-	previous *scope
-}
-
-func newScope() *scope {
-	return &scope{make(map[string]Type), nil}
-}
-
-func (s *scope) assign(name string, ty Type) {
-
-	s.dict[name] = ty
-}
-
-func (s *scope) get(name string) Type {
-	if s == nil {
-		return nil
-	}
-	ty, ok := s.dict[name]
-	if !ok {
-		return s.previous.get(name)
-	}
-	return ty
-}
-
-func (s *scope) newScope() *scope {
-	return &scope{make(map[string]Type), s}
-}
+package frontend
+
+import (
+	"language/syntax"
+	"sort"
+)
+
+type scope struct {
+	dict      map[string]Type
+	defs      map[string]syntax.Span
+	previous  *scope
+	structure bool
+
+	// bound holds the names bound by the pattern of a match arm, which are
+	// not visible after it.
+	bound map[string]struct{}
+}
+
+func newScope() *scope {
+	return &scope{make(map[string]Type), make(map[string]syntax.Span), nil, false, nil}
+}
+
+func (s *scope) assign(name string, ty Type) {
+
+	s.dict[name] = ty
+}
+
+func (s *scope) get(name string) Type {
+	if s == nil {
+		return nil
+	}
+	ty, ok := s.dict[name]
+	if !ok {
+		return s.previous.get(name)
+	}
+	return ty
+}
+
+func (s *scope) getDef(name string) (syntax.Span, bool) {
+	if s == nil {
+		return syntax.Span{}, false
+	}
+	def, ok := s.defs[name]
+	if !ok {
+		return s.previous.getDef(name)
+	}
+	return def, true
+}
+
+func (s *scope) newScope() *scope {
+	return &scope{make(map[string]Type), make(map[string]syntax.Span), s, false, nil}
+}
+
+// sortedNames orders the names of dict so that the code generated for them
+// does not depend on map iteration order.
+func sortedNames(dict map[string]Type) []string {
+	names := []string{}
+	for name := range dict {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
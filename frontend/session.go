@@ -0,0 +1,57 @@
+package frontend
+
+import (
+	"language/backend"
+	"language/syntax"
+)
+
+// Session compiles a sequence of inputs into one program, keeping the
+// variables bound by each successful input in scope for the next.
+type Session struct {
+	comp *compiler
+}
+
+func NewSession(program *backend.Program) *Session {
+	return &Session{newCompiler(program, nil)}
+}
+
+// Compile compiles span into a new entry block that ends in an exit, so it
+// can be run on a virtual machine that has already run the previous inputs.
+// The modules it imports that earlier inputs have not are compiled first,
+// running their top level before span.
+func (session *Session) Compile(span syntax.Span, modules ...Module) (*backend.Block, Type, []error) {
+	comp := session.comp
+	entry := comp.program.NewBlock()
+	comp.block = entry
+	comp.errors = []error{}
+	comp.info = &Info{}
+
+	root := comp.scope
+	dict := make(map[string]Type)
+	for name, ty := range root.dict {
+		dict[name] = ty
+	}
+	defs := make(map[string]syntax.Span)
+	for name, def := range root.defs {
+		defs[name] = def
+	}
+
+	var ty Type
+	compiled, ok := comp.compileModules(modules)
+	if ok {
+		ty = comp.compile(span)
+	}
+	if ty == nil {
+		// The top level of the modules compiled for this input never
+		// runs, so they are compiled again when imported next time.
+		for _, path := range compiled {
+			delete(comp.modules, path)
+		}
+		comp.scope = root
+		root.dict = dict
+		root.defs = defs
+		return nil, nil, comp.errors
+	}
+	comp.block.Exit(comp.block.Constant(0))
+	return entry, ty, nil
+}
@@ -5,36 +5,68 @@ import (
 	"language/backend"
 	"language/syntax"
 	"sort"
+	"strconv"
 	"strings"
 )
 
 type Type interface {
-	// Value(*backend.VirtualMachine) string
+	Value(*backend.VirtualMachine) string
 	Type() string
 }
 
+// Integer is a value of an integer kind. literal holds the value of an
+// unsuffixed literal, which takes on the kind of the integer it is used with.
 type Integer struct {
-	val *backend.Value
+	val     *backend.Value
+	kind    IntKind
+	literal *uint64
 }
 
 type Boolean struct {
 	val *backend.Value
 }
 
+type String struct {
+	addr, length *backend.Value
+}
+
 type Maybe struct {
 	val *backend.Value
 	ty  Type
 }
 
+// function is a fn expression, which is compiled into an Impl for every
+// parameter type it is called with, or once where it is bound to a name when
+// all of its parameters are annotated, in which case params holds their types.
+type function struct {
+	param   syntax.Span
+	returns syntax.Span
+	body    syntax.Span
+	scope   *scope
+	impls   []Impl
+	params  Type
+}
+
+// Func is a function value, made up of the values of the variables its
+// function captured where it was created.
 type Func struct {
-	param syntax.Span
-	body  syntax.Span
-	scope *scope
-	impls []Impl
+	*function
+	env Struct
+}
+
+// FuncRef is a function value chosen at runtime among functions of the same
+// signature. code holds the address of the thunk of the chosen function and
+// env the address of its captured environment.
+type FuncRef struct {
+	code, env *backend.Value
+	sig       *signature
 }
 
+// Impl is one compiled body of a Func, which is passed params and the
+// captured env at every call.
 type Impl struct {
 	params  Type
+	env     Type
 	returns Type
 	scope   *scope
 	block   *backend.Block
@@ -42,63 +74,169 @@ type Impl struct {
 
 type Struct struct {
 	dict map[string]Type
+	defs map[string]syntax.Span
 }
 
 type Tuple struct {
 	items []Type
 }
 
-// func (ty Integer) Value(vm *backend.VirtualMachine) string {
-// 	return fmt.Sprintf("%d", vm.GetValue(ty.val))
-// }
-
-// func (ty Boolean) Value(vm *backend.VirtualMachine) string {
-// 	if vm.GetValue(ty.val) == 1 {
-// 		return "true"
-// 	}
-// 	return "false"
-// }
-
-// func (ty Maybe) Value(vm *backend.VirtualMachine) string {
-// 	if vm.GetValue(ty.val) == 1 {
-// 		return ty.ty.Value(vm)
-// 	}
-// 	return "none"
-// }
-
-// func (ty Func) Value(vm *backend.VirtualMachine) string {
-// 	return "fn"
-// }
-
-// func (ty Struct) Value(vm *backend.VirtualMachine) string {
-// 	fields := make([]string, len(ty.dict))
-// 	order := []string{}
-// 	for name := range ty.dict {
-// 		order = append(order, name)
-// 	}
-// 	sort.Strings(order)
-// 	for i, name := range order {
-// 		fields[i] = fmt.Sprintf("%s: %s", name, ty.dict[name].Value(vm))
-// 	}
-// 	return "{" + strings.Join(fields, ", ") + "}"
-// }
-
-// func (ty Tuple) Value(vm *backend.VirtualMachine) string {
-// 	items := make([]string, len(ty.items))
-// 	for i, ty := range ty.items {
-// 		items[i] = ty.Value(vm)
-// 	}
-// 	return "(" + strings.Join(items, ", ") + ")"
-// }
+// Mmio is a memory-mapped register of width bytes at a fixed address, read
+// and written with volatile accesses whenever its variable is used.
+type Mmio struct {
+	address int
+	kind    IntKind
+}
+
+// variant is one case of an enum with the payload it carries, which is nil
+// for a variant without one.
+type variant struct {
+	name    string
+	payload Type
+}
+
+// EnumType is a declared enum, which constructs values of its variants with
+// '.' as in Shape.Circle(1). It has no runtime values itself.
+type EnumType struct {
+	variants []variant
+}
+
+// Enum is a value of an enum, whose tag is the index of its variant. The
+// payloads of all variants are kept and only that of the tagged variant
+// holds a meaningful value.
+type Enum struct {
+	tag      *backend.Value
+	variants []variant
+}
+
+// Never is the type of return, break and continue, which jump elsewhere
+// instead of giving a value.
+type Never struct{}
+
+// Array is the address of length elements stored in memory, each taking the
+// words of the values of elem.
+type Array struct {
+	addr   *backend.Value
+	elem   Type
+	length int
+}
+
+func (ty Integer) Value(vm *backend.VirtualMachine) string {
+	if ty.kind.width == backend.WordSize && !ty.kind.signed {
+		return fmt.Sprintf("%d", uint64(vm.GetValue(ty.val)))
+	}
+	return fmt.Sprintf("%d", vm.GetValue(ty.val))
+}
+
+func (ty Boolean) Value(vm *backend.VirtualMachine) string {
+	if vm.GetValue(ty.val) == 1 {
+		return "true"
+	}
+	return "false"
+}
+
+func (ty String) Value(vm *backend.VirtualMachine) string {
+	bytes, err := vm.ReadMemory(vm.GetValue(ty.addr), vm.GetValue(ty.length))
+	if err != nil {
+		return "str"
+	}
+	return strconv.Quote(string(bytes))
+}
+
+func (ty Maybe) Value(vm *backend.VirtualMachine) string {
+	if vm.GetValue(ty.val) == 1 {
+		return ty.ty.Value(vm)
+	}
+	return "none"
+}
+
+func (ty Func) Value(vm *backend.VirtualMachine) string {
+	return "fn"
+}
+
+func (ty FuncRef) Value(vm *backend.VirtualMachine) string {
+	return ty.Type()
+}
+
+func (ty Struct) Value(vm *backend.VirtualMachine) string {
+	fields := make([]string, len(ty.dict))
+	order := []string{}
+	for name := range ty.dict {
+		order = append(order, name)
+	}
+	sort.Strings(order)
+	for i, name := range order {
+		fields[i] = fmt.Sprintf("%s: %s", name, ty.dict[name].Value(vm))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+func (ty Tuple) Value(vm *backend.VirtualMachine) string {
+	items := make([]string, len(ty.items))
+	for i, ty := range ty.items {
+		items[i] = ty.Value(vm)
+	}
+	return "(" + strings.Join(items, ", ") + ")"
+}
+
+func (ty Array) Value(vm *backend.VirtualMachine) string {
+	words := len(ToValues(ty.elem))
+	scratch := backend.NewProgram()
+	items := make([]string, ty.length)
+	for i := range items {
+		vals := make([]*backend.Value, words)
+		for k := range vals {
+			vals[k] = scratch.NewValue()
+			word, err := vm.ReadWord(vm.GetValue(ty.addr), i*words+k)
+			if err != nil {
+				return "array"
+			}
+			vm.SetValue(vals[k], word)
+		}
+		items[i] = FromValues(vals, ty.elem).Value(vm)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func (ty EnumType) Value(vm *backend.VirtualMachine) string {
+	return ty.Type()
+}
+
+func (ty Enum) Value(vm *backend.VirtualMachine) string {
+	tag := vm.GetValue(ty.tag)
+	if tag < 0 || tag >= len(ty.variants) {
+		return "enum"
+	}
+	variant := ty.variants[tag]
+	if variant.payload == nil {
+		return variant.name
+	}
+	if _, ok := variant.payload.(Tuple); ok {
+		return variant.name + variant.payload.Value(vm)
+	}
+	return variant.name + "(" + variant.payload.Value(vm) + ")"
+}
+
+func (ty Mmio) Value(vm *backend.VirtualMachine) string {
+	return fmt.Sprintf("mmio %s at 0x%x", ty.kind.name, ty.address)
+}
+
+func (ty Never) Value(vm *backend.VirtualMachine) string {
+	return ""
+}
 
 func (ty Integer) Type() string {
-	return "int"
+	return ty.kind.name
 }
 
 func (ty Boolean) Type() string {
 	return "bool"
 }
 
+func (ty String) Type() string {
+	return "str"
+}
+
 func (ty Maybe) Type() string {
 	return fmt.Sprintf("%s?", ty.ty.Type())
 }
@@ -129,6 +267,43 @@ func (ty Tuple) Type() string {
 	return "(" + strings.Join(items, ", ") + ")"
 }
 
+func (ty Array) Type() string {
+	return fmt.Sprintf("[%s; %d]", ty.elem.Type(), ty.length)
+}
+
+func (ty FuncRef) Type() string {
+	params := ty.sig.params.Type()
+	if _, ok := ty.sig.params.(Tuple); !ok {
+		params = "(" + params + ")"
+	}
+	return fmt.Sprintf("fn %s -> %s", params, ty.sig.returns.Type())
+}
+
+func (ty EnumType) Type() string {
+	return "type " + Enum{nil, ty.variants}.Type()
+}
+
+func (ty Enum) Type() string {
+	variants := make([]string, len(ty.variants))
+	for i, variant := range ty.variants {
+		variants[i] = variant.name
+		if _, ok := variant.payload.(Tuple); ok {
+			variants[i] += variant.payload.Type()
+		} else if variant.payload != nil {
+			variants[i] += "(" + variant.payload.Type() + ")"
+		}
+	}
+	return "enum {" + strings.Join(variants, ", ") + "}"
+}
+
+func (ty Never) Type() string {
+	return "never"
+}
+
+func (ty Mmio) Type() string {
+	return "mmio " + ty.kind.name
+}
+
 func ToValues(ty Type) []*backend.Value {
 	vals := []*backend.Value{}
 	appendValues(ty, &vals)
@@ -141,6 +316,8 @@ func appendValues(ty Type, vals *[]*backend.Value) {
 		*vals = append(*vals, ty.val)
 	case Boolean:
 		*vals = append(*vals, ty.val)
+	case String:
+		*vals = append(*vals, ty.addr, ty.length)
 	case Maybe:
 		*vals = append(*vals, ty.val)
 		appendValues(ty.ty, vals)
@@ -157,7 +334,20 @@ func appendValues(ty Type, vals *[]*backend.Value) {
 		for _, item := range ty.items {
 			appendValues(item, vals)
 		}
+	case Array:
+		*vals = append(*vals, ty.addr)
 	case Func:
+		appendValues(ty.env, vals)
+	case FuncRef:
+		*vals = append(*vals, ty.code, ty.env)
+	case Enum:
+		*vals = append(*vals, ty.tag)
+		for _, variant := range ty.variants {
+			if variant.payload != nil {
+				appendValues(variant.payload, vals)
+			}
+		}
+	case EnumType, Mmio, Never:
 		break
 	default:
 		panic(fmt.Sprintf("Undefined type %T", ty))
@@ -172,14 +362,16 @@ func FromValues(vals []*backend.Value, ty Type) Type {
 func castValuesToType(vals []*backend.Value, ty Type) (Type, []*backend.Value) {
 	switch ty := ty.(type) {
 	case Integer:
-		return Integer{vals[0]}, vals[1:]
+		return Integer{vals[0], ty.kind, nil}, vals[1:]
 	case Boolean:
 		return Boolean{vals[0]}, vals[1:]
+	case String:
+		return String{vals[0], vals[1]}, vals[2:]
 	case Maybe:
 		newTy, leftover := castValuesToType(vals[1:], ty.ty)
 		return Maybe{vals[0], newTy}, leftover
 	case Struct:
-		newStruct := Struct{make(map[string]Type)}
+		newStruct := Struct{make(map[string]Type), ty.defs}
 		order := []string{}
 		for name := range ty.dict {
 			order = append(order, name)
@@ -199,9 +391,27 @@ func castValuesToType(vals []*backend.Value, ty Type) (Type, []*backend.Value) {
 			vals = leftoverRegs
 		}
 		return Tuple{items}, vals
+	case Array:
+		return Array{vals[0], ty.elem, ty.length}, vals[1:]
 	case Func:
+		env, leftover := castValuesToType(vals, ty.env)
+		return Func{ty.function, env.(Struct)}, leftover
+	case FuncRef:
+		return FuncRef{vals[0], vals[1], ty.sig}, vals[2:]
+	case Enum:
+		tag := vals[0]
+		vals = vals[1:]
+		variants := make([]variant, len(ty.variants))
+		for i, variant := range ty.variants {
+			variants[i] = variant
+			if variant.payload != nil {
+				variants[i].payload, vals = castValuesToType(vals, variant.payload)
+			}
+		}
+		return Enum{tag, variants}, vals
+	case EnumType, Mmio, Never:
 		return ty, vals
 	default:
-		panic("unreachable")
+		panic(fmt.Sprintf("Undefined type %T", ty))
 	}
 }
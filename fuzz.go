@@ -0,0 +1,446 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"language/backend"
+	"language/frontend"
+	"language/syntax"
+	"math/rand"
+	"strings"
+	"unicode/utf8"
+)
+
+const fuzzAlphabet = "(){}[]=+<?.,:->\n 0a\"'\\\xff"
+
+// fuzzTypes are the types of the variables that generated programs declare.
+var fuzzTypes = []string{"int", "u8", "bool", "str", "int?"}
+
+// generator builds random programs that are well typed, so that most of them
+// make it through the frontend and exercise the backend and virtual machine.
+// It keeps track of the variables in scope by type and only declares new
+// names, which keeps every assignment to an existing variable at its type.
+type generator struct {
+	rand  *rand.Rand
+	vars  map[string][]string
+	funcs []string
+	names int
+	loop  bool
+	fn    bool
+	enum  bool
+}
+
+func newGenerator(seed int64) *generator {
+	return &generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (gen *generator) pick(options ...string) string {
+	return options[gen.rand.Intn(len(options))]
+}
+
+func (gen *generator) name(prefix string) string {
+	gen.names++
+	return fmt.Sprintf("%s%d", prefix, gen.names)
+}
+
+func (gen *generator) declare(name, ty string) {
+	gen.vars[ty] = append(gen.vars[ty], name)
+}
+
+// nested runs fn with the variables declared inside it going out of scope
+// afterwards, as they become maybes or are missing after a branch or loop.
+func (gen *generator) nested(fn func()) {
+	vars := map[string][]string{}
+	for ty, names := range gen.vars {
+		vars[ty] = append([]string{}, names...)
+	}
+	funcs, enum := append([]string{}, gen.funcs...), gen.enum
+	fn()
+	gen.vars, gen.funcs, gen.enum = vars, funcs, enum
+}
+
+func (gen *generator) program() string {
+	gen.vars, gen.funcs, gen.names = map[string][]string{}, nil, 0
+	gen.loop, gen.fn, gen.enum = false, false, false
+	lines := []string{}
+	for n := 2 + gen.rand.Intn(6); n >= 0; n-- {
+		lines = append(lines, gen.statement(2))
+	}
+	lines = append(lines, gen.expr(gen.pick(fuzzTypes...), 2))
+	return strings.Join(lines, "\n")
+}
+
+func (gen *generator) block(depth int) string {
+	lines := []string{}
+	gen.nested(func() {
+		for n := gen.rand.Intn(3); n >= 0; n-- {
+			lines = append(lines, gen.statement(depth))
+		}
+	})
+	return "{\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+func (gen *generator) statement(depth int) string {
+	if depth <= 0 {
+		return gen.declaration(0)
+	}
+	switch gen.rand.Intn(12) {
+	case 0:
+		return fmt.Sprintf("print(%s)", gen.expr(gen.pick(fuzzTypes...), depth))
+	case 1:
+		return fmt.Sprintf("write(%s)", gen.expr("str", depth))
+	case 2:
+		if names := gen.vars["int"]; len(names) > 0 {
+			name := gen.pick(names...)
+			return fmt.Sprintf("%s = %s", name, gen.expr("int", depth-1))
+		}
+	case 3:
+		return fmt.Sprintf("if (%s) %s", gen.cond(depth-1), gen.block(depth-1))
+	case 4:
+		return gen.loopStatement(depth)
+	case 5:
+		return gen.function(depth)
+	case 6:
+		structure := gen.name("s")
+		line := fmt.Sprintf("%s = struct {\na = %s\nb = %s\n}", structure, gen.expr("int", depth-1), gen.expr("bool", depth-1))
+		gen.declare(structure+".a", "int")
+		gen.declare(structure+".b", "bool")
+		if gen.rand.Intn(2) == 0 {
+			a, b := gen.name("v"), gen.name("v")
+			line += fmt.Sprintf("\n{a: %s, b: %s} = %s", a, b, structure)
+			gen.declare(a, "int")
+			gen.declare(b, "bool")
+		}
+		return line
+	case 7:
+		a, b := gen.name("v"), gen.name("v")
+		line := fmt.Sprintf("(%s, %s) = (%s, %s)", a, b, gen.expr("int", depth-1), gen.expr("str", depth-1))
+		gen.declare(a, "int")
+		gen.declare(b, "str")
+		return line
+	case 8:
+		array := gen.name("arr")
+		line := fmt.Sprintf("%s = [%s, %s, %s]", array, gen.expr("int", depth-1), gen.expr("int", depth-1), gen.expr("int", depth-1))
+		for i := 0; i < 3; i++ {
+			gen.declare(fmt.Sprintf("%s[%d]", array, i), "int")
+		}
+		if gen.rand.Intn(2) == 0 {
+			line += fmt.Sprintf("\n%s[%d] = %s", array, gen.rand.Intn(3), gen.expr("int", depth-1))
+		}
+		return line
+	case 9:
+		line := ""
+		if !gen.enum {
+			gen.enum = true
+			line = "Shape = enum { Dot, Line(int), Flag(bool) }\n"
+		}
+		shape := gen.name("e")
+		line += fmt.Sprintf("%s = %s", shape, gen.pick("Shape.Dot", fmt.Sprintf("Shape.Line(%s)", gen.expr("int", depth-1)), fmt.Sprintf("Shape.Flag(%s)", gen.expr("bool", depth-1))))
+		gen.declare(shape, "Shape")
+		return line
+	case 10:
+		if gen.loop {
+			return fmt.Sprintf("if (%s) %s", gen.cond(depth-1), gen.pick("break", "continue"))
+		}
+		if gen.fn {
+			return fmt.Sprintf("if (%s) return %s", gen.cond(depth-1), gen.expr("int", depth-1))
+		}
+	}
+	return gen.declaration(depth)
+}
+
+func (gen *generator) declaration(depth int) string {
+	ty := gen.pick(fuzzTypes...)
+	name := gen.name("v")
+	value := gen.expr(ty, depth)
+	gen.declare(name, ty)
+	if ty != "int?" && gen.rand.Intn(4) == 0 {
+		return fmt.Sprintf("%s: %s = %s", name, ty, value)
+	}
+	return fmt.Sprintf("%s = %s", name, value)
+}
+
+// loopStatement counts a fresh variable up to a small bound, so that loops
+// finish even when their body skips ahead with continue.
+func (gen *generator) loopStatement(depth int) string {
+	counter := gen.name("i")
+	outer := gen.loop
+	gen.loop = true
+	body := []string{fmt.Sprintf("%s = %s + 1", counter, counter)}
+	gen.nested(func() {
+		for n := gen.rand.Intn(3); n >= 0; n-- {
+			body = append(body, gen.statement(depth-1))
+		}
+	})
+	gen.loop = outer
+	return fmt.Sprintf("%s = 0\nwhile (%s < %d) {\n%s\n}", counter, counter, 1+gen.rand.Intn(5), strings.Join(body, "\n"))
+}
+
+// function declares a function from int to int, which may capture the
+// variables in scope and return early.
+func (gen *generator) function(depth int) string {
+	name, param := gen.name("f"), gen.name("p")
+	outer, loop := gen.fn, gen.loop
+	gen.fn, gen.loop = true, false
+	body := []string{}
+	gen.nested(func() {
+		gen.declare(param, "int")
+		for n := gen.rand.Intn(3); n > 0; n-- {
+			body = append(body, gen.statement(depth-1))
+		}
+		body = append(body, gen.expr("int", depth-1))
+	})
+	gen.fn, gen.loop = outer, loop
+	gen.funcs = append(gen.funcs, name)
+	return fmt.Sprintf("%s = fn (%s: int) -> int {\n%s\n}", name, param, strings.Join(body, "\n"))
+}
+
+func (gen *generator) variable(ty string) (string, bool) {
+	names := gen.vars[ty]
+	if len(names) == 0 {
+		return "", false
+	}
+	return gen.pick(names...), true
+}
+
+func (gen *generator) literal(ty string) string {
+	switch ty {
+	case "int":
+		return gen.pick(fmt.Sprint(gen.rand.Intn(300)), "'a'", "0x7fffffffffffffff")
+	case "u8":
+		return fmt.Sprintf("%du8", gen.rand.Intn(256))
+	case "bool":
+		return gen.pick("true", "false")
+	case "str":
+		return gen.pick(`"hi"`, `"a\tb\n"`, `""`, `"\x41"`)
+	}
+	return fmt.Sprintf("if (%s) %s", gen.literal("bool"), gen.literal("int"))
+}
+
+// cond generates the condition of an if, which is a boolean variable or
+// literal or a comparison.
+func (gen *generator) cond(depth int) string {
+	if gen.rand.Intn(2) == 0 {
+		names := []string{"true", "false"}
+		for _, name := range gen.vars["bool"] {
+			if !strings.ContainsAny(name, ".[") {
+				names = append(names, name)
+			}
+		}
+		return gen.pick(names...)
+	}
+	ty := gen.pick("int", "u8")
+	return fmt.Sprintf("%s < %s", gen.expr(ty, depth), gen.expr(ty, depth))
+}
+
+// expr generates an expression of type ty.
+func (gen *generator) expr(ty string, depth int) string {
+	if name, ok := gen.variable(ty); ok && gen.rand.Intn(3) == 0 {
+		return name
+	}
+	if depth <= 0 {
+		return gen.literal(ty)
+	}
+	depth--
+	switch ty {
+	case "int":
+		switch gen.rand.Intn(9) {
+		case 0:
+			return fmt.Sprintf("%s + %s", gen.expr("int", depth), gen.expr("int", depth))
+		case 1:
+			return fmt.Sprintf("len(%s)", gen.expr("str", depth))
+		case 2:
+			return fmt.Sprintf("(%s else %s)", gen.expr("int?", depth), gen.expr("int", depth))
+		case 3:
+			return fmt.Sprintf("int(%s)", gen.expr("u8", depth))
+		case 4:
+			if len(gen.funcs) > 0 {
+				return fmt.Sprintf("%s(%s)", gen.pick(gen.funcs...), gen.expr("int", depth))
+			}
+		case 5:
+			return fmt.Sprintf("match (%s) { %s? -> %s, none -> %s }", gen.expr("int?", depth), gen.pick("_", "n"), gen.expr("int", depth), gen.expr("int", depth))
+		case 6:
+			if shape, ok := gen.variable("Shape"); ok {
+				return fmt.Sprintf("match (%s) { Line(n) -> n + %s, Flag(true) -> %s, _ -> %s }", shape, gen.expr("int", depth), gen.expr("int", depth), gen.expr("int", depth))
+			}
+		case 7:
+			return fmt.Sprintf("match (%s) { 0 -> %s, 1 -> %s, _ -> %s }", gen.expr("int", depth), gen.expr("int", depth), gen.expr("int", depth), gen.expr("int", depth))
+		}
+	case "u8":
+		switch gen.rand.Intn(3) {
+		case 0:
+			return fmt.Sprintf("%s + %s", gen.expr("u8", depth), gen.expr("u8", depth))
+		case 1:
+			return fmt.Sprintf("u8(%s)", gen.expr("int", depth))
+		}
+	case "bool":
+		switch gen.rand.Intn(4) {
+		case 0:
+			return fmt.Sprintf("%s < %s", gen.expr("int", depth), gen.expr("int", depth))
+		case 1:
+			return fmt.Sprintf("%s < %s", gen.expr("u8", depth), gen.expr("u8", depth))
+		case 2:
+			return fmt.Sprintf("match (%s) { true -> %s, false -> %s }", gen.expr("bool", depth), gen.expr("bool", depth), gen.expr("bool", depth))
+		}
+	case "str":
+		if gen.rand.Intn(2) == 0 {
+			return fmt.Sprintf("(if (%s) %s else %s)", gen.cond(depth), gen.expr("str", depth), gen.expr("str", depth))
+		}
+	case "int?":
+		return fmt.Sprintf("if (%s) %s", gen.cond(depth), gen.expr("int", depth))
+	}
+	return gen.literal(ty)
+}
+
+func (gen *generator) mutate(source string) string {
+	if len(source) == 0 {
+		return source
+	}
+	bytes := []byte(source)
+	for n := gen.rand.Intn(3); n >= 0; n-- {
+		i := gen.rand.Intn(len(bytes))
+		switch gen.rand.Intn(3) {
+		case 0:
+			bytes = append(bytes[:i], bytes[i+1:]...)
+		case 1:
+			bytes[i] = fuzzAlphabet[gen.rand.Intn(len(fuzzAlphabet))]
+		default:
+			bytes = append(bytes[:i], append([]byte{bytes[i]}, bytes[i:]...)...)
+		}
+		if len(bytes) == 0 {
+			break
+		}
+	}
+	return string(bytes)
+}
+
+type fuzzFailure struct {
+	stage  string
+	source string
+	err    error
+}
+
+func fuzzStage(stage, source string, fn func() error) (failure *fuzzFailure) {
+	defer func() {
+		if r := recover(); r != nil {
+			failure = &fuzzFailure{stage, source, fmt.Errorf("panic: %v", r)}
+		}
+	}()
+	if err := fn(); err != nil {
+		return &fuzzFailure{stage, source, err}
+	}
+	return nil
+}
+
+func fuzzParse(source string) *fuzzFailure {
+	return fuzzStage("parse", source, func() error {
+		syntax.Parse(source)
+		return nil
+	})
+}
+
+func withoutLines(ast syntax.Span) string {
+	lines := strings.Split(ast.String(), "\n")
+	for i, line := range lines {
+		if len(line) > 6 {
+			lines[i] = line[6:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func fuzzFormat(source string) *fuzzFailure {
+	return fuzzStage("format", source, func() error {
+		ast, errs := syntax.Parse(source)
+		if len(errs) != 0 {
+			return nil
+		}
+		formatted, errs := syntax.Format(source)
+		if len(errs) != 0 {
+			return fmt.Errorf("format failed: %v", errs)
+		}
+		reparsed, errs := syntax.Parse(formatted)
+		if len(errs) != 0 {
+			return fmt.Errorf("formatted source does not parse: %v\n--- formatted ---\n%s", errs, formatted)
+		}
+		if withoutLines(ast) != withoutLines(reparsed) {
+			return fmt.Errorf("formatting changed the AST\n--- formatted ---\n%s", formatted)
+		}
+		if again, _ := syntax.Format(formatted); again != formatted {
+			return fmt.Errorf("formatting is not idempotent\n--- formatted ---\n%s", formatted)
+		}
+		return nil
+	})
+}
+
+func fuzzJSON(source string) *fuzzFailure {
+	return fuzzStage("json", source, func() error {
+		ast, _ := syntax.Parse(source)
+		data, err := json.Marshal(ast)
+		if err != nil && !utf8.ValidString(source) {
+			// Exporting invalid UTF-8 is refused rather than mangled.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		imported := syntax.Span{}
+		if err := json.Unmarshal(data, &imported); err != nil {
+			return err
+		}
+		if ast.String() != imported.String() {
+			return fmt.Errorf("JSON round trip changed the AST")
+		}
+		return nil
+	})
+}
+
+func fuzzCompile(source string) *fuzzFailure {
+	return fuzzStage("compile", source, func() error {
+		ast, errs := syntax.Parse(source)
+		if len(errs) == 0 {
+			frontend.Compile(ast)
+		}
+		return nil
+	})
+}
+
+func fuzzPipeline(source string, compiled *int) *fuzzFailure {
+	return fuzzStage("pipeline", source, func() error {
+		comp, _ := compile("", source)
+		if comp != nil {
+			*compiled++
+			vm := backend.NewVirtualMachine(comp.entry)
+			vm.MaxSteps = 10000
+			vm.Run()
+		}
+		return nil
+	})
+}
+
+func fuzz(seed int64, iterations int) bool {
+	gen := newGenerator(seed)
+	failures := map[string]*fuzzFailure{}
+	compiled := 0
+	pipeline := func(source string) *fuzzFailure {
+		return fuzzPipeline(source, &compiled)
+	}
+	for i := 0; i < iterations; i++ {
+		source := gen.program()
+		if gen.rand.Intn(2) == 0 {
+			source = gen.mutate(source)
+		}
+		for _, target := range []func(string) *fuzzFailure{fuzzParse, fuzzFormat, fuzzJSON, fuzzCompile, pipeline} {
+			if failure := target(source); failure != nil {
+				key := fmt.Sprintf("%s: %v", failure.stage, strings.SplitN(failure.err.Error(), "\n", 2)[0])
+				if _, seen := failures[key]; !seen {
+					failures[key] = failure
+					fmt.Printf("FAIL in %s: %v\n--- source ---\n%s\n\n", failure.stage, failure.err, failure.source)
+				}
+				break
+			}
+		}
+	}
+	fmt.Printf("%d iterations, %d compiled, %d distinct failures\n", iterations, compiled, len(failures))
+	return len(failures) == 0
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// addCorpus seeds f with the programs in testdata and a few generated ones.
+func addCorpus(f *testing.F) {
+	for _, pattern := range []string{"testdata/*.txt", "testdata/lib/*.txt", "testdata/golden/*.txt", "testdata/golden/lib/*.txt"} {
+		paths, _ := filepath.Glob(pattern)
+		for _, path := range paths {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(string(source))
+		}
+	}
+	gen := newGenerator(1)
+	for i := 0; i < 20; i++ {
+		f.Add(gen.program())
+	}
+}
+
+func check(t *testing.T, failure *fuzzFailure) {
+	if failure != nil {
+		t.Fatalf("%s: %v", failure.stage, failure.err)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	addCorpus(f)
+	f.Fuzz(func(t *testing.T, source string) {
+		check(t, fuzzParse(source))
+		check(t, fuzzJSON(source))
+	})
+}
+
+func FuzzFormat(f *testing.F) {
+	addCorpus(f)
+	f.Fuzz(func(t *testing.T, source string) {
+		check(t, fuzzFormat(source))
+	})
+}
+
+func FuzzCompile(f *testing.F) {
+	addCorpus(f)
+	f.Fuzz(func(t *testing.T, source string) {
+		compiled := 0
+		check(t, fuzzCompile(source))
+		check(t, fuzzPipeline(source, &compiled))
+	})
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"language/backend"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var goldenExtensions = []string{".ast", ".ir", ".s", ".exit", ".out"}
+
+func goldenTest(dir string, update bool) bool {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+	sort.Strings(paths)
+	if len(paths) == 0 {
+		fmt.Printf("no programs found in '%s'\n", dir)
+		return false
+	}
+
+	failed := 0
+	for _, path := range paths {
+		if err := goldenFile(path, update); err != nil {
+			failed++
+			fmt.Printf("FAIL %s\n%s\n", path, err)
+		} else if update {
+			fmt.Printf("updated %s\n", path)
+		} else {
+			fmt.Printf("ok   %s\n", path)
+		}
+	}
+	fmt.Printf("%d/%d programs match\n", len(paths)-failed, len(paths))
+	return failed == 0
+}
+
+// goldenFile compares the outputs of the program at path with the files
+// stored next to it, or rewrites those files when update is set.
+func goldenFile(path string, update bool) error {
+	outputs, err := goldenOutputs(path)
+	if err != nil {
+		return fmt.Errorf("  %s", err)
+	}
+	base := strings.TrimSuffix(path, ".txt")
+	if update {
+		for _, ext := range goldenExtensions {
+			if err := ioutil.WriteFile(base+ext, []byte(outputs[ext]), 0644); err != nil {
+				return fmt.Errorf("  %s", err)
+			}
+		}
+		return nil
+	}
+	problems := []string{}
+	for _, ext := range goldenExtensions {
+		expected, err := ioutil.ReadFile(base + ext)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("  missing %s, run with -update", base+ext))
+			continue
+		}
+		if diff := firstDifference(string(expected), outputs[ext]); diff != "" {
+			problems = append(problems, fmt.Sprintf("  %s: %s", ext, diff))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func goldenOutputs(path string) (outputs map[string]string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("compiler panic: %v", r)
+		}
+	}()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	comp, errs := compile(filepath.Dir(path), string(data))
+	if comp == nil {
+		return nil, fmt.Errorf("compilation failed: %v", errs)
+	}
+
+	vm := backend.NewVirtualMachine(comp.entry)
+	vm.MaxSteps = 1000000
+	output := &bytes.Buffer{}
+	vm.Output = output
+	exit := ""
+	if result, err := vm.Run(); err != nil {
+		exit = fmt.Sprintf("error: %s\n", err)
+	} else {
+		exit = fmt.Sprintf("%d\n", result)
+	}
+
+	return map[string]string{
+		".ast":  fmt.Sprint(comp.ast),
+		".ir":   comp.ir,
+		".s":    comp.asm,
+		".exit": exit,
+		".out":  output.String(),
+	}, nil
+}
+
+func firstDifference(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		e, a := "<eof>", "<eof>"
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+		if e != a {
+			return fmt.Sprintf("line %d: expected %q, got %q", i+1, e, a)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate the expected output files of the golden suite")
+
+// TestGolden runs every program in testdata/golden and compares its AST,
+// IR, assembly, exit value and output with the files stored next to it.
+func TestGolden(t *testing.T) {
+	paths, _ := filepath.Glob("testdata/golden/*.txt")
+	sort.Strings(paths)
+	if len(paths) == 0 {
+		t.Fatal("no programs found in testdata/golden")
+	}
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.ToSlash(path), func(t *testing.T) {
+			if err := goldenFile(path, *update); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"language/frontend"
+	"language/syntax"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+type server struct {
+	in       *bufio.Reader
+	out      io.Writer
+	docs     map[string]*document
+	shutdown bool
+}
+
+type document struct {
+	text string
+	info *frontend.Info
+}
+
+// Serve runs a language server reading JSON-RPC messages from in and writing
+// responses and notifications to out until the client sends exit.
+func Serve(in io.Reader, out io.Writer) error {
+	srv := &server{bufio.NewReader(in), out, map[string]*document{}, false}
+	for {
+		data, err := srv.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		req := request{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			srv.fail(nil, parseErrorCode, err.Error())
+			continue
+		}
+		if req.Method == "exit" {
+			if !srv.shutdown {
+				return fmt.Errorf("exit before shutdown")
+			}
+			return nil
+		}
+		srv.handle(req)
+	}
+}
+
+func (srv *server) read() ([]byte, error) {
+	headers, err := textproto.NewReader(srv.in).ReadMIMEHeader()
+	if err != nil {
+		if len(headers) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length: %v", err)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(srv.in, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (srv *server) write(msg interface{}) {
+	data, _ := json.Marshal(msg)
+	fmt.Fprintf(srv.out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+func (srv *server) reply(id *json.RawMessage, result interface{}) {
+	if id != nil {
+		srv.write(response{"2.0", id, result})
+	}
+}
+
+func (srv *server) fail(id *json.RawMessage, code int, msg string) {
+	srv.write(errorResponse{"2.0", id, responseError{code, msg}})
+}
+
+func (srv *server) notify(method string, params interface{}) {
+	srv.write(notification{"2.0", method, params})
+}
+
+func (srv *server) handle(req request) {
+	params := func(v interface{}) bool {
+		if err := json.Unmarshal(req.Params, v); err != nil {
+			srv.fail(req.ID, invalidParamsCode, err.Error())
+			return false
+		}
+		return true
+	}
+
+	switch req.Method {
+	case "initialize":
+		srv.reply(req.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":       fullSyncKind,
+				"hoverProvider":          true,
+				"definitionProvider":     true,
+				"documentSymbolProvider": true,
+			},
+			"serverInfo": map[string]string{"name": diagnosticName},
+		})
+
+	case "initialized", "$/cancelRequest", "$/setTrace":
+
+	case "shutdown":
+		srv.shutdown = true
+		srv.reply(req.ID, nil)
+
+	case "textDocument/didOpen":
+		p := didOpenParams{}
+		if params(&p) {
+			srv.update(p.TextDocument.URI, p.TextDocument.Text)
+		}
+
+	case "textDocument/didChange":
+		p := didChangeParams{}
+		if params(&p) && len(p.ContentChanges) > 0 {
+			srv.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+		}
+
+	case "textDocument/didClose":
+		p := didCloseParams{}
+		if params(&p) {
+			delete(srv.docs, p.TextDocument.URI)
+			srv.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{p.TextDocument.URI, []diagnostic{}})
+		}
+
+	case "textDocument/hover":
+		p := textDocumentPositionParams{}
+		if params(&p) {
+			srv.reply(req.ID, srv.hover(p))
+		}
+
+	case "textDocument/definition":
+		p := textDocumentPositionParams{}
+		if params(&p) {
+			srv.reply(req.ID, srv.definition(p))
+		}
+
+	case "textDocument/documentSymbol":
+		p := documentSymbolParams{}
+		if params(&p) {
+			srv.reply(req.ID, srv.symbols(p.TextDocument.URI))
+		}
+
+	default:
+		if req.ID != nil {
+			srv.fail(req.ID, methodNotFoundCode, fmt.Sprintf("method '%s' not found", req.Method))
+		}
+	}
+}
+
+func (srv *server) update(uri, text string) {
+	doc := &document{text: text}
+	diagnostics := []diagnostic{}
+	for _, err := range analyze(doc, moduleRoot(uri)) {
+		diagnostics = append(diagnostics, diagnostic{doc.errorRange(err), errorSeverity, diagnosticName, err.Error()})
+	}
+	srv.docs[uri] = doc
+	srv.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{uri, diagnostics})
+}
+
+// moduleRoot returns the folder of the document at uri, which its imports
+// are loaded relative to like those of a program compiled from that file.
+func moduleRoot(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+	return filepath.Dir(filepath.FromSlash(u.Path))
+}
+
+func analyze(doc *document, root string) (errs []error) {
+	defer func() {
+		if r := recover(); r != nil {
+			errs = append(errs, fmt.Errorf("internal compiler error: %v", r))
+		}
+	}()
+	doc.info = &frontend.Info{}
+	ast, errs := syntax.Parse(doc.text)
+	modules, loadErrs := frontend.LoadModules(root, ast)
+	if len(loadErrs) != 0 {
+		return append(errs, loadErrs...)
+	}
+	info, compileErrs := frontend.Analyze(ast, modules...)
+	doc.info = info
+	return append(errs, compileErrs...)
+}
+
+// toPosition converts pos to an LSP position, whose character counts UTF-16
+// code units from the start of the line rather than runes.
+func (doc *document) toPosition(pos syntax.Position) position {
+	offset := pos.Offset()
+	if offset > len(doc.text) {
+		offset = len(doc.text)
+	}
+	line := doc.text[strings.LastIndexByte(doc.text[:offset], '\n')+1 : offset]
+	character := 0
+	for _, r := range line {
+		character += utf16.RuneLen(r)
+	}
+	return position{pos.Line() - 1, character}
+}
+
+func (doc *document) toRange(span syntax.Span) lspRange {
+	return lspRange{doc.toPosition(span.Start()), doc.toPosition(span.End())}
+}
+
+func (doc *document) errorRange(err error) lspRange {
+	switch err := err.(type) {
+	case interface{ Span() syntax.Span }:
+		return doc.toRange(err.Span())
+	case interface{ Position() syntax.Position }:
+		start := doc.toPosition(err.Position())
+		return lspRange{start, position{start.Line, start.Character + 1}}
+	}
+	return lspRange{}
+}
+
+func before(a, b position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Character < b.Character
+}
+
+func (doc *document) contains(span syntax.Span, pos position) bool {
+	r := doc.toRange(span)
+	return !before(pos, r.Start) && before(pos, r.End)
+}
+
+func width(span syntax.Span) int {
+	return span.End().Offset() - span.Start().Offset()
+}
+
+func (srv *server) hover(p textDocumentPositionParams) interface{} {
+	doc, ok := srv.docs[p.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+	var best *frontend.TypeInfo
+	for i, ty := range doc.info.Types {
+		if doc.contains(ty.Span, p.Position) && (best == nil || width(ty.Span) < width(best.Span)) {
+			best = &doc.info.Types[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return hover{markupContent{plainTextKind, best.Type.Type()}, doc.toRange(best.Span)}
+}
+
+func (srv *server) definition(p textDocumentPositionParams) interface{} {
+	doc, ok := srv.docs[p.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+	for _, def := range doc.info.Definitions {
+		if doc.contains(def.Use, p.Position) {
+			return location{p.TextDocument.URI, doc.toRange(def.Def)}
+		}
+	}
+	return nil
+}
+
+func (srv *server) symbols(uri string) []*documentSymbol {
+	doc, ok := srv.docs[uri]
+	if !ok {
+		return []*documentSymbol{}
+	}
+	symbols := []frontend.Symbol{}
+	seen := map[int]struct{}{}
+	for _, symbol := range doc.info.Symbols {
+		if _, ok := seen[symbol.Span.Start().Offset()]; !ok {
+			seen[symbol.Span.Start().Offset()] = struct{}{}
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		a, b := symbols[i].Range, symbols[j].Range
+		if a.Start().Offset() != b.Start().Offset() {
+			return a.Start().Offset() < b.Start().Offset()
+		}
+		return width(a) > width(b)
+	})
+
+	roots := []*documentSymbol{}
+	parents := []*documentSymbol{}
+	ends := []int{}
+	for _, symbol := range symbols {
+		for len(parents) > 0 && ends[len(ends)-1] < symbol.Range.End().Offset() {
+			parents, ends = parents[:len(parents)-1], ends[:len(ends)-1]
+		}
+		kind := variableKind
+		if symbol.Kind == frontend.FieldSymbol {
+			kind = fieldKind
+		}
+		node := &documentSymbol{symbol.Name, kind, doc.toRange(symbol.Range), doc.toRange(symbol.Span), nil}
+		if len(parents) == 0 {
+			roots = append(roots, node)
+		} else {
+			parent := parents[len(parents)-1]
+			parent.Children = append(parent.Children, node)
+		}
+		parents = append(parents, node)
+		ends = append(ends, symbol.Range.End().Offset())
+	}
+	return roots
+}
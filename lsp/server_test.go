@@ -0,0 +1,150 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The emoji is outside the basic multilingual plane, so it is one rune but
+// two UTF-16 code units, which LSP columns count.
+const testDocument = "t = (\"\U0001F600\", 7)\n(\"\U0001F600\", y)"
+
+type testMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Params json.RawMessage `json:"params"`
+}
+
+// session sends requests to a server one after the other and returns every
+// message it wrote back.
+func session(t *testing.T, requests ...interface{}) []testMessage {
+	in := &bytes.Buffer{}
+	for _, req := range requests {
+		data, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+	out := &bytes.Buffer{}
+	if err := Serve(in, out); err != nil {
+		t.Fatalf("server failed: %v", err)
+	}
+	client := &server{in: bufio.NewReader(out)}
+	messages := []testMessage{}
+	for {
+		data, err := client.read()
+		if err == io.EOF {
+			return messages
+		}
+		if err != nil {
+			t.Fatalf("cannot read message from server: %v", err)
+		}
+		msg := testMessage{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, msg)
+	}
+}
+
+func call(id int, method string, params interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params}
+}
+
+func notice(method string, params interface{}) map[string]interface{} {
+	return map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+}
+
+func TestSession(t *testing.T) {
+	uri := "file:///test.txt"
+	messages := session(t,
+		call(1, "initialize", map[string]interface{}{}),
+		notice("initialized", map[string]interface{}{}),
+		notice("textDocument/didOpen", didOpenParams{textDocumentItem{uri, testDocument}}),
+		call(2, "textDocument/hover", textDocumentPositionParams{textDocumentIdentifier{uri}, position{0, 11}}),
+		call(3, "shutdown", nil),
+		notice("exit", nil),
+	)
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(messages))
+	}
+
+	if id := messages[0].ID; id == nil || *id != 1 || !bytes.Contains(messages[0].Result, []byte(`"hoverProvider":true`)) {
+		t.Errorf("unexpected reply to initialize: %s", messages[0].Result)
+	}
+
+	if messages[1].Method != "textDocument/publishDiagnostics" {
+		t.Fatalf("expected diagnostics, got %q", messages[1].Method)
+	}
+	diagnostics := publishDiagnosticsParams{}
+	if err := json.Unmarshal(messages[1].Params, &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	want := lspRange{position{1, 7}, position{1, 8}}
+	if len(diagnostics.Diagnostics) != 1 || diagnostics.Diagnostics[0].Range != want {
+		t.Errorf("expected one diagnostic at %v, got %+v", want, diagnostics.Diagnostics)
+	}
+
+	result := hover{}
+	if err := json.Unmarshal(messages[2].Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	want = lspRange{position{0, 11}, position{0, 12}}
+	if result.Contents.Value != "int" || result.Range != want {
+		t.Errorf("expected hover 'int' at %v, got %+v", want, result)
+	}
+
+	if id := messages[3].ID; id == nil || *id != 3 || string(messages[3].Result) != "null" {
+		t.Errorf("unexpected reply to shutdown: %s", messages[3].Result)
+	}
+}
+
+func TestExitBeforeShutdown(t *testing.T) {
+	in := bytes.NewBufferString("Content-Length: 33\r\n\r\n{\"jsonrpc\":\"2.0\",\"method\":\"exit\"}")
+	if err := Serve(in, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for exit before shutdown")
+	}
+}
+
+// Imports are loaded relative to the folder of the document, and only the
+// document itself is described by hover.
+func TestImports(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lib", "twice.txt"), []byte("twice = fn (x: int) -> int x + x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(dir, "main.txt"))}).String()
+	messages := session(t,
+		call(1, "initialize", map[string]interface{}{}),
+		notice("textDocument/didOpen", didOpenParams{textDocumentItem{uri, "lib = import \"lib/twice\"\nlib.twice(2)"}}),
+		call(2, "textDocument/hover", textDocumentPositionParams{textDocumentIdentifier{uri}, position{1, 0}}),
+		call(3, "shutdown", nil),
+		notice("exit", nil),
+	)
+	diagnostics := publishDiagnosticsParams{}
+	if err := json.Unmarshal(messages[1].Params, &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics.Diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diagnostics.Diagnostics)
+	}
+	result := hover{}
+	if err := json.Unmarshal(messages[2].Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{twice: fn}"; result.Contents.Value != want {
+		t.Errorf("expected hover %q, got %q", want, result.Contents.Value)
+	}
+}
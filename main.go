@@ -1,22 +1,83 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"language/backend"
-	"language/frontend"
-	"language/syntax"
+	"language/lsp"
 	"os"
 	"os/exec"
 )
 
 func main() {
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "difftest":
+			dir := "testdata"
+			if len(os.Args) > 2 {
+				dir = os.Args[2]
+			}
+			if !diffTest(dir) {
+				os.Exit(1)
+			}
+			return
+		case "golden":
+			flags := flag.NewFlagSet("golden", flag.ExitOnError)
+			update := flags.Bool("update", false, "regenerate the expected output files")
+			flags.Parse(os.Args[2:])
+			dir := "testdata/golden"
+			if flags.NArg() > 0 {
+				dir = flags.Arg(0)
+			}
+			if !goldenTest(dir, *update) {
+				os.Exit(1)
+			}
+			return
+		case "fmt":
+			flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+			check := flags.Bool("check", false, "list unformatted files instead of rewriting them")
+			flags.Parse(os.Args[2:])
+			paths := flags.Args()
+			if len(paths) == 0 {
+				paths = []string{"example.txt"}
+			}
+			if !formatFiles(paths, *check) {
+				os.Exit(1)
+			}
+			return
+		case "repl":
+			repl(os.Stdin, os.Stdout)
+			return
+		case "lsp":
+			if err := lsp.Serve(os.Stdin, os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		case "fuzz":
+			flags := flag.NewFlagSet("fuzz", flag.ExitOnError)
+			seed := flags.Int64("seed", 1, "seed for the program generator")
+			iterations := flags.Int("n", 10000, "number of programs to generate")
+			flags.Parse(os.Args[2:])
+			if !fuzz(*seed, *iterations) {
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	astJSON := flag.Bool("json", false, "also write the AST as JSON to build/output.ast.json")
+	flag.Parse()
+
 	data, _ := ioutil.ReadFile("example.txt")
 	source := string(data)
 
-	ast, errs := syntax.Parse(source)
-	if len(errs) != 0 {
+	comp, errs := compile(".", source)
+	if comp == nil {
+		fmt.Println("Compilation unsucessful:")
 		for _, err := range errs {
 			fmt.Println(err)
 		}
@@ -24,43 +85,31 @@ func main() {
 	}
 
 	f, _ := os.Create("build/output.ast")
-	f.WriteString(fmt.Sprint(ast))
+	f.WriteString(fmt.Sprint(comp.ast))
 	f.Close()
 
-	program, entry, exit, ty, errs := frontend.Compile(ast)
-	if ty == nil {
-		fmt.Println("Compilation unsucessful:")
-		for _, err := range errs {
+	if *astJSON {
+		data, err := json.MarshalIndent(comp.ast, "", "  ")
+		if err != nil {
 			fmt.Println(err)
+			return
 		}
-		return
+		ioutil.WriteFile("build/output.ast.json", append(data, '\n'), 0644)
 	}
 
-	if ty.Type() != "int" {
-		fmt.Println("Return type must be integer!")
-		return
-	}
-
-	exit.Exit(frontend.ToValues(ty)[0])
-
-	backend.MarkUsedValues(program)
-	backend.RemoveDeadCode(program)
-
-	backend.LivenessAnalysis(exit, program)
-	backend.CoalesceCopies(program)
-	backend.CoalesceBinary(program)
-
 	f, _ = os.Create("build/output.ir")
-	f.WriteString(backend.IrToStr(program))
+	f.WriteString(comp.ir)
 	f.Close()
 
-	backend.Execute(entry)
-
-	backend.LivenessAnalysis(exit, program)
-	backend.RegisterAllocation(program)
+	vm := backend.NewVirtualMachine(comp.entry)
+	vm.MaxSteps = 1000000
+	vm.Output = os.Stdout
+	if _, err := vm.Run(); err != nil {
+		fmt.Println(err)
+	}
 
 	f, _ = os.Create("build/output.s")
-	f.WriteString(backend.X86(program, entry))
+	f.WriteString(comp.asm)
 	f.Close()
 
 	exec.Command("gcc", "-nostdlib", "build/output.s", "-o", "build/output").Output()
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"language/backend"
+	"language/frontend"
+	"language/syntax"
+)
+
+type compilation struct {
+	ast     syntax.Span
+	program *backend.Program
+	entry   *backend.Block
+	exit    *backend.Block
+	ir      string
+	asm     string
+}
+
+// compile compiles the program source, loading the modules it imports from
+// files below root.
+func compile(root, source string) (*compilation, []error) {
+	ast, errs := syntax.Parse(source)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	modules, errs := frontend.LoadModules(root, ast)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	program, entry, exit, ty, errs := frontend.Compile(ast, modules...)
+	if ty == nil {
+		return nil, errs
+	}
+
+	exit = frontend.Print(program, exit, ty)
+	if _, ok := ty.(frontend.Integer); ok {
+		exit.Exit(frontend.ToValues(ty)[0])
+	} else {
+		exit.Exit(exit.Constant(0))
+	}
+
+	backend.FoldConstants(program)
+	backend.MarkUsedValues(program)
+	backend.RemoveDeadCode(program)
+
+	backend.LivenessAnalysis(exit, program)
+	backend.CoalesceCopies(program)
+	backend.CoalesceBinary(program)
+
+	ir := backend.IrToStr(program)
+
+	backend.LivenessAnalysis(exit, program)
+	if err := backend.RegisterAllocation(program); err != nil {
+		return nil, []error{err}
+	}
+
+	asm := backend.X86(program, entry)
+
+	return &compilation{ast, program, entry, exit, ir, asm}, nil
+}
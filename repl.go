@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"language/backend"
+	"language/frontend"
+	"language/syntax"
+	"strings"
+)
+
+func bracketDepth(source string) int {
+	depth := 0
+	for _, tok := range syntax.Lex(source) {
+		if tok.Kind != syntax.SymbolToken {
+			continue
+		}
+		switch tok.Text {
+		case "(", "{", "[":
+			depth++
+		case ")", "}", "]":
+			depth--
+		}
+	}
+	return depth
+}
+
+func repl(in io.Reader, out io.Writer) {
+	program := backend.NewProgram()
+	session := frontend.NewSession(program)
+	vm := backend.NewVirtualMachine(nil)
+	vm.MaxSteps = 1000000
+	vm.Output = out
+
+	scanner := bufio.NewScanner(in)
+	lines := []string{}
+	fmt.Fprint(out, "> ")
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		source := strings.Join(lines, "\n")
+		if strings.TrimSpace(source) == "" {
+			lines = lines[:0]
+			fmt.Fprint(out, "> ")
+			continue
+		}
+		if bracketDepth(source) > 0 {
+			fmt.Fprint(out, "... ")
+			continue
+		}
+		lines = lines[:0]
+		evaluate(session, vm, source, out)
+		fmt.Fprint(out, "> ")
+	}
+	fmt.Fprintln(out)
+}
+
+func evaluate(session *frontend.Session, vm *backend.VirtualMachine, source string, out io.Writer) {
+	ast, errs := syntax.Parse(source)
+	if len(errs) != 0 {
+		for _, err := range errs {
+			fmt.Fprintln(out, err)
+		}
+		return
+	}
+	modules, errs := frontend.LoadModules("", ast)
+	if len(errs) != 0 {
+		for _, err := range errs {
+			fmt.Fprintln(out, err)
+		}
+		return
+	}
+	entry, ty, errs := session.Compile(ast, modules...)
+	if entry == nil {
+		for _, err := range errs {
+			fmt.Fprintln(out, err)
+		}
+		return
+	}
+	vm.Start(entry)
+	if _, err := vm.Run(); err != nil {
+		fmt.Fprintln(out, err)
+		return
+	}
+	fmt.Fprintf(out, "%s : %s\n", ty.Value(vm), ty.Type())
+}
@@ -13,6 +13,14 @@ func (span Span) GetExpr() Expr {
 	return span.expr
 }
 
+func (span Span) Start() Position {
+	return span.start
+}
+
+func (span Span) End() Position {
+	return span.end
+}
+
 type Identifier struct {
 	Ident string
 }
@@ -25,6 +33,14 @@ type BooleanLiteral struct {
 	Value string
 }
 
+type StringLiteral struct {
+	Value string
+}
+
+type CharLiteral struct {
+	Value string
+}
+
 type Binary struct {
 	Op    BinaryOp
 	Left  Span
@@ -42,6 +58,10 @@ const (
 	Func         BinaryOp = "fn"
 	Call         BinaryOp = "call"
 	While        BinaryOp = "while"
+	Index        BinaryOp = "[]"
+	Annotation   BinaryOp = ":"
+	Returns      BinaryOp = "->"
+	Arm          BinaryOp = "arm"
 	Dot                   = "."
 )
 
@@ -68,6 +88,51 @@ type Struct struct {
 	Block Span
 }
 
+type Array struct {
+	Items []Span
+}
+
+// Mmio declares a memory-mapped register of type Type at a fixed Address.
+type Mmio struct {
+	Type    string
+	Address Span
+}
+
+// Enum declares a tagged union whose variants are listed in Block, each a
+// name optionally followed by the type of its payload in brackets.
+type Enum struct {
+	Block Span
+}
+
+// Match chooses the first of the Arms in Block whose pattern matches Value.
+// Each arm is an Arm binary of a pattern and the expression it evaluates to.
+type Match struct {
+	Value Span
+	Block Span
+}
+
+// Import evaluates to the namespace of the module at Path, a string
+// literal holding a path relative to the root of the program.
+type Import struct {
+	Path string
+}
+
+// Return leaves the enclosing function with Value.
+type Return struct {
+	Value Span
+}
+
+// Break leaves the innermost loop.
+type Break struct{}
+
+// Continue skips the rest of the body of the innermost loop, going on with
+// its next iteration.
+type Continue struct{}
+
+type Error struct {
+	Text string
+}
+
 func newBinary(left, right Span, op BinaryOp) Binary {
 	return Binary{op, left, right}
 }
@@ -86,8 +151,8 @@ func newBlock(left, right Span) Span {
 
 func newTuple(left, right Span) Span {
 	tuple := Tuple{[]Span{left, right}}
-	if left, ok := left.expr.(Tuple); ok {
-		tuple.Items = append(left.Items, right)
+	if rest, ok := right.expr.(Tuple); ok && right.end == rest.Items[len(rest.Items)-1].end {
+		tuple.Items = append([]Span{left}, rest.Items...)
 	}
 	return Span{left.start, right.end, tuple}
 }
@@ -112,6 +177,10 @@ func formatAST(span Span, indent string, line int) string {
 		s += fmt.Sprintf("integer: '%s'\n", expr.Value)
 	case BooleanLiteral:
 		s += fmt.Sprintf("boolean: '%s'\n", expr.Value)
+	case StringLiteral:
+		s += fmt.Sprintf("string: %s\n", expr.Value)
+	case CharLiteral:
+		s += fmt.Sprintf("char: %s\n", expr.Value)
 	case Binary:
 		s += fmt.Sprintf("binary '%s':\n", expr.Op)
 		s += formatAST(expr.Left, indent, line)
@@ -135,6 +204,34 @@ func formatAST(span Span, indent string, line int) string {
 	case Struct:
 		s += fmt.Sprintf("struct:\n")
 		s += formatAST(expr.Block, indent, line)
+	case Array:
+		s += fmt.Sprintf("array:\n")
+		for _, item := range expr.Items {
+			s += formatAST(item, indent, line)
+			line = item.start.line
+		}
+	case Mmio:
+		s += fmt.Sprintf("mmio '%s':\n", expr.Type)
+		s += formatAST(expr.Address, indent, line)
+	case Enum:
+		s += fmt.Sprintf("enum:\n")
+		s += formatAST(expr.Block, indent, line)
+	case Match:
+		s += fmt.Sprintf("match:\n")
+		s += formatAST(expr.Value, indent, line)
+		line = expr.Value.start.line
+		s += formatAST(expr.Block, indent, line)
+	case Import:
+		s += fmt.Sprintf("import: %s\n", expr.Path)
+	case Return:
+		s += fmt.Sprintf("return:\n")
+		s += formatAST(expr.Value, indent, line)
+	case Break:
+		s += fmt.Sprintf("break\n")
+	case Continue:
+		s += fmt.Sprintf("continue\n")
+	case Error:
+		s += fmt.Sprintf("error: '%s'\n", expr.Text)
 	default:
 		s += "error!"
 	}
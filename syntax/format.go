@@ -0,0 +1,246 @@
+package syntax
+
+import (
+	"strings"
+)
+
+const formatIndent = "    "
+
+type formatter struct {
+	comments []Token
+}
+
+// Format regenerates source text from the AST of source in the canonical
+// style, keeping comments at the statement boundaries they were written at.
+func Format(source string) (string, []error) {
+	span, errs := Parse(source)
+	if len(errs) != 0 {
+		return "", errs
+	}
+	f := &formatter{}
+	for _, tok := range Lex(source) {
+		if tok.Kind == CommentToken {
+			f.comments = append(f.comments, tok)
+		}
+	}
+	// A program of one statement is not a block, so a program that is a
+	// block of one statement keeps its braces.
+	if block, ok := span.expr.(Block); ok && len(block.Statements) == 1 {
+		span = Span{span.start, span.end, Block{[]Span{span}}}
+	}
+	end := startOfString(source)
+	end.offset = len(source)
+	return f.statements(span, end, ""), nil
+}
+
+func statementsOf(span Span) []Span {
+	if block, ok := span.expr.(Block); ok {
+		return block.Statements
+	}
+	return []Span{span}
+}
+
+func (f *formatter) statements(span Span, end Position, indent string) string {
+	s := ""
+	line := 0
+	separate := func(start int) {
+		if line != 0 && start > line+1 {
+			s += "\n"
+		}
+	}
+	for _, stmt := range statementsOf(span) {
+		for len(f.comments) > 0 && f.comments[0].Start.offset < stmt.start.offset {
+			comment := f.comments[0]
+			f.comments = f.comments[1:]
+			separate(comment.Start.line)
+			s += indent + comment.Text + "\n"
+			line = comment.End.line
+		}
+		separate(stmt.start.line)
+		s += indent + f.expr(stmt, statement, indent)
+		line = stmt.end.line
+		if len(f.comments) > 0 && f.comments[0].Start.line == line {
+			s += " " + f.comments[0].Text
+			f.comments = f.comments[1:]
+		}
+		s += "\n"
+	}
+	for len(f.comments) > 0 && f.comments[0].Start.offset < end.offset {
+		separate(f.comments[0].Start.line)
+		s += indent + f.comments[0].Text + "\n"
+		line = f.comments[0].End.line
+		f.comments = f.comments[1:]
+	}
+	return s
+}
+
+func nodePrecedence(node Expr) precedence {
+	switch node := node.(type) {
+	case Binary:
+		switch node.Op {
+		case Add:
+			return addition
+		case LessThan:
+			return comparison
+		case Dot:
+			return dot
+		case Index:
+			return indicies
+		case Annotation, Arm:
+			return annotation
+		case SingleEquals:
+			return statement
+		case Else:
+			return tuple
+		case If, While, Func:
+			return expr
+		}
+	case Unary, Mmio, Return:
+		return expr
+	}
+	return literal
+}
+
+func (f *formatter) expr(span Span, prec precedence, indent string) string {
+	s := f.format(span, indent)
+	switch {
+	case nodePrecedence(span.expr) >= prec:
+		return s
+	case nodePrecedence(span.expr) <= statement:
+		return "{ " + s + " }"
+	default:
+		return "(" + s + ")"
+	}
+}
+
+func (f *formatter) bracketed(span Span, indent string) string {
+	if items, ok := span.expr.(Tuple); ok {
+		return "(" + f.items(items, indent) + ")"
+	}
+	return "(" + f.expr(span, tuple, indent) + ")"
+}
+
+// typ formats a type annotation the way parseType reads it back.
+func (f *formatter) typ(span Span, indent string) string {
+	switch node := span.expr.(type) {
+	case Unary:
+		if node.Op == Maybe {
+			return f.typ(node.Expr, indent) + string(node.Op)
+		}
+	case Identifier, Tuple, IntegerLiteral, BooleanLiteral, StringLiteral, CharLiteral:
+		return f.format(span, indent)
+	case Block:
+		return f.body(span, indent)
+	case Binary:
+		if node.Op == Call {
+			return f.format(span, indent)
+		}
+	}
+	return f.bracketed(span, indent)
+}
+
+func (f *formatter) items(node Tuple, indent string) string {
+	items := make([]string, len(node.Items))
+	for i, item := range node.Items {
+		items[i] = f.expr(item, annotation, indent)
+	}
+	return strings.Join(items, ", ")
+}
+
+// hasComments reports whether any comment that is still to be written lies
+// inside span.
+func (f *formatter) hasComments(span Span) bool {
+	for _, comment := range f.comments {
+		if comment.Start.offset >= span.end.offset {
+			break
+		}
+		if comment.Start.offset >= span.start.offset {
+			return true
+		}
+	}
+	return false
+}
+
+// body writes a block of one statement on one line, unless that would move
+// the comments inside it out of the block.
+func (f *formatter) body(span Span, indent string) string {
+	if block, ok := span.expr.(Block); ok && len(block.Statements) == 1 && !f.hasComments(span) {
+		span = block.Statements[0]
+	}
+	switch node := span.expr.(type) {
+	case Block:
+		return "{\n" + f.statements(span, span.end, indent+formatIndent) + indent + "}"
+	case Tuple:
+		return "{ " + f.items(node, indent) + " }"
+	}
+	return "{ " + f.expr(span, statement, indent) + " }"
+}
+
+func (f *formatter) format(span Span, indent string) string {
+	switch node := span.expr.(type) {
+	case Identifier:
+		return node.Ident
+	case IntegerLiteral:
+		return node.Value
+	case BooleanLiteral:
+		return node.Value
+	case StringLiteral:
+		return node.Value
+	case CharLiteral:
+		return node.Value
+	case Block:
+		return f.body(span, indent)
+	case Struct:
+		return "struct " + f.body(node.Block, indent)
+	case Tuple:
+		return "(" + f.items(node, indent) + ")"
+	case Array:
+		return "[" + f.items(Tuple(node), indent) + "]"
+	case Enum:
+		return "enum " + f.body(node.Block, indent)
+	case Match:
+		return "match " + f.bracketed(node.Value, indent) + " " + f.body(node.Block, indent)
+	case Import:
+		return "import " + node.Path
+	case Return:
+		return "return " + f.expr(node.Value, expr, indent)
+	case Break:
+		return "break"
+	case Continue:
+		return "continue"
+	case Mmio:
+		return "mmio " + node.Type + " at " + f.expr(node.Address, literal, indent)
+	case Unary:
+		return f.expr(node.Expr, expr+1, indent) + string(node.Op)
+	case Binary:
+		switch node.Op {
+		case If, While:
+			return string(node.Op) + " " + f.bracketed(node.Left, indent) + " " + f.expr(node.Right, expr, indent)
+		case Func:
+			if params, ok := node.Left.expr.(Binary); ok && params.Op == Returns {
+				return "fn " + f.bracketed(params.Left, indent) + " -> " + f.typ(params.Right, indent) + " " + f.expr(node.Right, expr, indent)
+			}
+			return "fn " + f.bracketed(node.Left, indent) + " " + f.expr(node.Right, expr, indent)
+		case Annotation:
+			return f.expr(node.Left, annotation+1, indent) + ": " + f.typ(node.Right, indent)
+		case Arm:
+			return f.expr(node.Left, annotation+1, indent) + " -> " + f.expr(node.Right, tuple, indent)
+		case Call:
+			callee := f.expr(node.Left, indicies, indent)
+			// The right of a '.' would take the call, as in a.f(x).
+			if left, ok := node.Left.expr.(Binary); ok && left.Op == Dot {
+				callee = "(" + callee + ")"
+			}
+			return callee + f.bracketed(node.Right, indent)
+		case Index:
+			return f.expr(node.Left, indicies, indent) + "[" + f.expr(node.Right, expr, indent) + "]"
+		case Dot:
+			return f.expr(node.Left, dot+1, indent) + "." + f.expr(node.Right, dot, indent)
+		case Else:
+			return f.expr(node.Left, expr, indent) + " else " + f.expr(node.Right, expr, indent)
+		}
+		prec := nodePrecedence(node)
+		return f.expr(node.Left, prec+1, indent) + " " + string(node.Op) + " " + f.expr(node.Right, prec, indent)
+	}
+	return ""
+}
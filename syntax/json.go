@@ -0,0 +1,179 @@
+package syntax
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// jsonNode is the stable JSON schema for every syntax node. Each node has a
+// "type" naming the node kind and "start"/"end" positions, plus the fields
+// relevant to that kind.
+type jsonNode struct {
+	Type       string       `json:"type"`
+	Start      jsonPosition `json:"start"`
+	End        jsonPosition `json:"end"`
+	Name       string       `json:"name,omitempty"`
+	Value      string       `json:"value,omitempty"`
+	Op         string       `json:"op,omitempty"`
+	Text       string       `json:"text,omitempty"`
+	Left       *Span        `json:"left,omitempty"`
+	Right      *Span        `json:"right,omitempty"`
+	Expr       *Span        `json:"expr,omitempty"`
+	Block      *Span        `json:"block,omitempty"`
+	Statements []Span       `json:"statements,omitempty"`
+	Items      []Span       `json:"items,omitempty"`
+}
+
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Offset int `json:"offset"`
+}
+
+func (span Span) MarshalJSON() ([]byte, error) {
+	node := jsonNode{
+		Start: jsonPosition{span.start.line, span.start.column, span.start.offset},
+		End:   jsonPosition{span.end.line, span.end.column, span.end.offset},
+	}
+	switch expr := span.expr.(type) {
+	case Identifier:
+		node.Type, node.Name = "identifier", expr.Ident
+	case IntegerLiteral:
+		node.Type, node.Value = "integer", expr.Value
+	case BooleanLiteral:
+		node.Type, node.Value = "boolean", expr.Value
+	case StringLiteral:
+		node.Type, node.Value = "string", expr.Value
+	case CharLiteral:
+		node.Type, node.Value = "char", expr.Value
+	case Binary:
+		node.Type, node.Op = "binary", string(expr.Op)
+		node.Left, node.Right = &expr.Left, &expr.Right
+	case Unary:
+		node.Type, node.Op = "unary", string(expr.Op)
+		node.Expr = &expr.Expr
+	case Block:
+		node.Type, node.Statements = "block", expr.Statements
+	case Tuple:
+		node.Type, node.Items = "tuple", expr.Items
+	case Struct:
+		node.Type, node.Block = "struct", &expr.Block
+	case Array:
+		node.Type, node.Items = "array", expr.Items
+	case Mmio:
+		node.Type, node.Name, node.Expr = "mmio", expr.Type, &expr.Address
+	case Enum:
+		node.Type, node.Block = "enum", &expr.Block
+	case Match:
+		node.Type, node.Expr, node.Block = "match", &expr.Value, &expr.Block
+	case Import:
+		node.Type, node.Value = "import", expr.Path
+	case Return:
+		node.Type, node.Expr = "return", &expr.Value
+	case Break:
+		node.Type = "break"
+	case Continue:
+		node.Type = "continue"
+	case Error:
+		node.Type, node.Text = "error", expr.Text
+	default:
+		return nil, fmt.Errorf("cannot export syntax node %T", span.expr)
+	}
+	// JSON strings hold text, so invalid UTF-8 could only be exported by
+	// replacing it, and would not read back as the same program.
+	for _, text := range []string{node.Name, node.Value, node.Text} {
+		if !utf8.ValidString(text) {
+			return nil, fmt.Errorf("cannot export invalid UTF-8 in %s node at (%d, %d)", node.Type, node.Start.Line, node.Start.Column)
+		}
+	}
+	return json.Marshal(node)
+}
+
+func (span *Span) UnmarshalJSON(data []byte) error {
+	node := jsonNode{}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	span.start = Position{node.Start.Line, node.Start.Column, node.Start.Offset, ""}
+	span.end = Position{node.End.Line, node.End.Column, node.End.Offset, ""}
+
+	required := func(children ...*Span) error {
+		for _, child := range children {
+			if child == nil {
+				return fmt.Errorf("%s node at (%d, %d) is missing a child", node.Type, node.Start.Line, node.Start.Column)
+			}
+		}
+		return nil
+	}
+
+	switch node.Type {
+	case "identifier":
+		span.expr = Identifier{node.Name}
+	case "integer":
+		span.expr = IntegerLiteral{node.Value}
+	case "boolean":
+		span.expr = BooleanLiteral{node.Value}
+	case "string":
+		span.expr = StringLiteral{node.Value}
+	case "char":
+		span.expr = CharLiteral{node.Value}
+	case "binary":
+		if err := required(node.Left, node.Right); err != nil {
+			return err
+		}
+		span.expr = Binary{BinaryOp(node.Op), *node.Left, *node.Right}
+	case "unary":
+		if err := required(node.Expr); err != nil {
+			return err
+		}
+		span.expr = Unary{UnaryOp(node.Op), *node.Expr}
+	case "block":
+		// The parser never makes an empty block, and the compiler expects
+		// every block to end in the statement that gives its value.
+		if len(node.Statements) == 0 {
+			return fmt.Errorf("block node at (%d, %d) has no statements", node.Start.Line, node.Start.Column)
+		}
+		span.expr = Block{node.Statements}
+	case "tuple":
+		span.expr = Tuple{node.Items}
+	case "array":
+		span.expr = Array{node.Items}
+	case "mmio":
+		if err := required(node.Expr); err != nil {
+			return err
+		}
+		span.expr = Mmio{node.Name, *node.Expr}
+	case "struct":
+		if err := required(node.Block); err != nil {
+			return err
+		}
+		span.expr = Struct{*node.Block}
+	case "enum":
+		if err := required(node.Block); err != nil {
+			return err
+		}
+		span.expr = Enum{*node.Block}
+	case "match":
+		if err := required(node.Expr, node.Block); err != nil {
+			return err
+		}
+		span.expr = Match{*node.Expr, *node.Block}
+	case "import":
+		span.expr = Import{node.Value}
+	case "return":
+		if err := required(node.Expr); err != nil {
+			return err
+		}
+		span.expr = Return{*node.Expr}
+	case "break":
+		span.expr = Break{}
+	case "continue":
+		span.expr = Continue{}
+	case "error":
+		span.expr = Error{node.Text}
+	default:
+		return fmt.Errorf("unknown syntax node type '%s'", node.Type)
+	}
+	return nil
+}
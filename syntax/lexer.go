@@ -0,0 +1,158 @@
+package syntax
+
+import (
+	"strings"
+	"unicode"
+)
+
+type TokenKind string
+
+const (
+	IdentToken   TokenKind = "ident"
+	IntegerToken TokenKind = "integer"
+	StringToken  TokenKind = "string"
+	CharToken    TokenKind = "char"
+	KeywordToken TokenKind = "keyword"
+	SymbolToken  TokenKind = "symbol"
+	CommentToken TokenKind = "comment"
+	InvalidToken TokenKind = "invalid"
+	EOFToken     TokenKind = "eof"
+)
+
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Start Position
+	End   Position
+}
+
+func (tok Token) is(kind TokenKind, text string) bool {
+	return tok.Kind == kind && tok.Text == text
+}
+
+var keywords = map[string]struct{}{
+	"if":       {},
+	"else":     {},
+	"while":    {},
+	"struct":   {},
+	"fn":       {},
+	"true":     {},
+	"false":    {},
+	"mmio":     {},
+	"import":   {},
+	"enum":     {},
+	"match":    {},
+	"return":   {},
+	"break":    {},
+	"continue": {},
+}
+
+// symbols is ordered so that longer operators are matched before their prefixes.
+var symbols = []string{
+	"->", "==", "!=", "<=", ">=",
+	"(", ")", "{", "}", "[", "]",
+	"+", "-", "*", "/", "<", ">", "=", "?", ".", ",", ":",
+}
+
+// Lex splits source into tokens, including comments, ending with an EOF token.
+func Lex(source string) []Token {
+	tokens := []Token{}
+	pos := startOfString(source)
+	for {
+		pos = skipSpaces(pos)
+		if pos.len() == 0 {
+			return append(tokens, Token{EOFToken, "", pos, pos})
+		}
+		tok := lexToken(pos)
+		tokens = append(tokens, tok)
+		pos = tok.End
+	}
+}
+
+func skipSpaces(start Position) (end Position) {
+	end = start
+	for unicode.IsSpace(end.peek()) {
+		end = end.next()
+	}
+	return
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isHexDigit(r rune) bool {
+	return unicode.IsDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// lexSuffix includes the type suffix of an integer literal such as 255u8.
+func lexSuffix(end Position) Position {
+	for isIdentStart(end.peek()) || unicode.IsDigit(end.peek()) {
+		end = end.next()
+	}
+	return end
+}
+
+func lexToken(start Position) Token {
+	end := start.next()
+	switch {
+	case strings.HasPrefix(start.leftover, "0x") && len(start.leftover) > 2 && isHexDigit(rune(start.leftover[2])):
+		end = end.next()
+		for isHexDigit(end.peek()) {
+			end = end.next()
+		}
+		end = lexSuffix(end)
+		return Token{IntegerToken, between(start, end), start, end}
+
+	case unicode.IsDigit(start.peek()):
+		for unicode.IsDigit(end.peek()) {
+			end = end.next()
+		}
+		end = lexSuffix(end)
+		return Token{IntegerToken, between(start, end), start, end}
+
+	case isIdentStart(start.peek()):
+		for isIdentStart(end.peek()) || unicode.IsDigit(end.peek()) {
+			end = end.next()
+		}
+		text := between(start, end)
+		if _, ok := keywords[text]; ok {
+			return Token{KeywordToken, text, start, end}
+		}
+		return Token{IdentToken, text, start, end}
+
+	case start.peek() == '"' || start.peek() == '\'':
+		quote := start.peek()
+		for end.len() > 0 && end.peek() != quote && end.peek() != '\n' {
+			if end.peek() == '\\' {
+				end = end.next()
+			}
+			end = end.next()
+		}
+		if end.peek() != quote {
+			return Token{InvalidToken, between(start, end), start, end}
+		}
+		end = end.next()
+		if quote == '"' {
+			return Token{StringToken, between(start, end), start, end}
+		}
+		return Token{CharToken, between(start, end), start, end}
+
+	case strings.HasPrefix(start.leftover, "//"):
+		for end.len() > 0 && end.peek() != '\n' {
+			end = end.next()
+		}
+		return Token{CommentToken, between(start, end), start, end}
+	}
+
+	for _, symbol := range symbols {
+		if strings.HasPrefix(start.leftover, symbol) {
+			end = start
+			for range symbol {
+				end = end.next()
+			}
+			return Token{SymbolToken, symbol, start, end}
+		}
+	}
+	return Token{InvalidToken, between(start, end), start, end}
+}
@@ -0,0 +1,48 @@
+package syntax
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Unquote decodes the text of a string or char literal including its quotes,
+// which may contain the escape sequences \n, \t, \r, \0, \\, \', \" and \xNN.
+func Unquote(text string) (string, error) {
+	body := text[1 : len(text)-1]
+	decoded := []byte{}
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\\' {
+			decoded = append(decoded, body[i])
+			continue
+		}
+		i++
+		if i == len(body) {
+			return "", fmt.Errorf("unfinished escape sequence")
+		}
+		switch body[i] {
+		case 'n':
+			decoded = append(decoded, '\n')
+		case 't':
+			decoded = append(decoded, '\t')
+		case 'r':
+			decoded = append(decoded, '\r')
+		case '0':
+			decoded = append(decoded, 0)
+		case '\\', '\'', '"':
+			decoded = append(decoded, body[i])
+		case 'x':
+			if i+3 > len(body) {
+				return "", fmt.Errorf("expected two hex digits after '\\x'")
+			}
+			value, err := strconv.ParseUint(body[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("expected two hex digits after '\\x'")
+			}
+			decoded = append(decoded, byte(value))
+			i += 2
+		default:
+			return "", fmt.Errorf("unknown escape sequence '\\%c'", body[i])
+		}
+	}
+	return string(decoded), nil
+}
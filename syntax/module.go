@@ -0,0 +1,64 @@
+package syntax
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Module returns the path of the imported module, cleaned so that every
+// import of the same file gives the same path. It must be relative and may
+// not leave the root with "..".
+func (imp Import) Module() (string, error) {
+	value, err := Unquote(imp.Path)
+	if err != nil {
+		return "", err
+	}
+	clean := path.Clean(value)
+	if value == "" || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("module path '%s' must be relative to the root and stay inside it", value)
+	}
+	return clean, nil
+}
+
+// Imports returns every import in span, in the order they are written.
+func Imports(span Span) []Span {
+	imports := []Span{}
+	var walk func(span Span)
+	walk = func(span Span) {
+		switch expr := span.expr.(type) {
+		case Import:
+			imports = append(imports, span)
+		case Binary:
+			walk(expr.Left)
+			walk(expr.Right)
+		case Unary:
+			walk(expr.Expr)
+		case Block:
+			for _, statement := range expr.Statements {
+				walk(statement)
+			}
+		case Tuple:
+			for _, item := range expr.Items {
+				walk(item)
+			}
+		case Array:
+			for _, item := range expr.Items {
+				walk(item)
+			}
+		case Struct:
+			walk(expr.Block)
+		case Enum:
+			walk(expr.Block)
+		case Match:
+			walk(expr.Value)
+			walk(expr.Block)
+		case Mmio:
+			walk(expr.Address)
+		case Return:
+			walk(expr.Value)
+		}
+	}
+	walk(span)
+	return imports
+}
@@ -2,7 +2,7 @@ package syntax
 
 import (
 	"fmt"
-	"unicode"
+	"strings"
 )
 
 type precedence int
@@ -11,6 +11,7 @@ const (
 	block          precedence = iota
 	statement      precedence = iota
 	tuple          precedence = iota
+	annotation     precedence = iota
 	expr           precedence = iota
 	boolean        precedence = iota
 	comparison     precedence = iota
@@ -24,21 +25,28 @@ const (
 	bracket        precedence = iota
 )
 
-func skipSpaces(start Position) (end Position) {
-	end = start
-	for unicode.IsSpace(end.peek()) {
-		end = end.next()
-	}
-	return
-}
-
 func Parse(source string) (Span, []error) {
 	parser := &parser{}
-	expr := parser.parse(startOfString(source), block)
+	for _, tok := range Lex(source) {
+		if tok.Kind != CommentToken {
+			parser.tokens = append(parser.tokens, tok)
+		}
+	}
+	expr := parser.parse(block)
+	for parser.peek().Kind != EOFToken {
+		tok := parser.next()
+		parser.throw(tok.Start, fmt.Sprintf("unexpected '%s'", tok.Text))
+		if parser.peek().Kind == EOFToken {
+			break
+		}
+		expr = newBlock(expr, parser.parse(block))
+	}
 	return expr, parser.errors
 }
 
 type parser struct {
+	tokens []Token
+	pos    int
 	errors []error
 }
 
@@ -51,107 +59,323 @@ func (err parseError) Error() string {
 	return fmt.Sprintf("(%d, %d) %s", err.pos.line, err.pos.column, err.msg)
 }
 
+func (err parseError) Position() Position {
+	return err.pos
+}
+
 func (parser *parser) throw(pos Position, msg string) {
 	parser.errors = append(parser.errors, parseError{pos, msg})
 }
 
-func (parser *parser) parseBracket(start Position, close rune, prec precedence) Span {
-	left := parser.parse(start.next(), prec)
-	pos := skipSpaces(left.end)
-	if pos.peek() == close {
-		return Span{start, pos.next(), left.expr}
+func (parser *parser) peek() Token {
+	return parser.tokens[parser.pos]
+}
+
+func (parser *parser) next() Token {
+	tok := parser.tokens[parser.pos]
+	if tok.Kind != EOFToken {
+		parser.pos++
 	}
-	parser.throw(pos, "missing close bracket")
-	return Span{start, pos, nil}
+	return tok
 }
 
-func (parser *parser) parse(start Position, prec precedence) (left Span) {
-	start = skipSpaces(start)
+func (parser *parser) isClose(tok Token) bool {
+	return tok.Kind == EOFToken || tok.is(SymbolToken, "}") || tok.is(SymbolToken, ")") || tok.is(SymbolToken, "]")
+}
+
+// synchronize skips past a malformed expression, stopping before the next
+// closing bracket, keyword or token on a new line so parsing can resume there.
+func (parser *parser) synchronize() Span {
+	start := parser.peek()
+	if parser.isClose(start) {
+		return Span{start.Start, start.Start, Error{""}}
+	}
+	end := parser.next()
+	for {
+		tok := parser.peek()
+		if parser.isClose(tok) || tok.Kind == KeywordToken || tok.Start.line != end.End.line {
+			return Span{start.Start, end.End, Error{between(start.Start, end.End)}}
+		}
+		end = parser.next()
+	}
+}
+
+func (parser *parser) parseBracket(close string, prec precedence) Span {
+	open := parser.next()
+	left := parser.parse(prec)
+	for close == "}" && parser.peek().is(SymbolToken, ")") {
+		tok := parser.next()
+		parser.throw(tok.Start, "unexpected ')'")
+		if parser.peek().is(SymbolToken, "}") || parser.peek().Kind == EOFToken {
+			break
+		}
+		left = newBlock(left, parser.parse(prec))
+	}
+	// Braces always make a block, so that a struct pattern such as {x, y}
+	// can be told apart from the tuple pattern (x, y).
+	expr := left.expr
+	if _, ok := expr.(Block); close == "}" && !ok {
+		expr = Block{[]Span{left}}
+	}
+	if parser.peek().is(SymbolToken, close) {
+		end := parser.next()
+		return Span{open.Start, end.End, expr}
+	}
+	parser.throw(parser.peek().Start, "missing close bracket")
+	return Span{open.Start, left.end, expr}
+}
+
+func (parser *parser) parseArray() Span {
+	open := parser.next()
+	var items []Span
+	if !parser.peek().is(SymbolToken, "]") {
+		left := parser.parse(tuple)
+		items = []Span{left}
+		if tuple, ok := left.expr.(Tuple); ok && left.end == tuple.Items[len(tuple.Items)-1].end {
+			items = tuple.Items
+		}
+	}
+	if parser.peek().is(SymbolToken, "]") {
+		end := parser.next()
+		return Span{open.Start, end.End, Array{items}}
+	}
+	parser.throw(parser.peek().Start, "missing close bracket")
+	return Span{open.Start, parser.tokens[parser.pos-1].End, Array{items}}
+}
+
+// parseType parses a type annotation, a name or a bracketed tuple of types
+// followed by any number of '?', without running into a following expression.
+// As the field patterns of a struct pattern are written after ':' too, it
+// also accepts struct patterns, literals and variant patterns such as
+// Circle(r), whose bracket must directly follow the name.
+func (parser *parser) parseType() Span {
+	var ty Span
+	switch tok := parser.peek(); {
+	case tok.is(SymbolToken, "(") || tok.is(SymbolToken, "{"):
+		ty = parser.parse(bracket)
+	case tok.Kind == IdentToken:
+		parser.next()
+		ty = Span{tok.Start, tok.End, Identifier{tok.Text}}
+		if open := parser.peek(); open.is(SymbolToken, "(") && open.Start.offset == tok.End.offset {
+			ty = newInfix(ty, parser.parse(bracket), Call)
+		}
+	case tok.Kind == IntegerToken || tok.Kind == CharToken || tok.Kind == StringToken ||
+		tok.is(KeywordToken, "true") || tok.is(KeywordToken, "false"):
+		ty = parser.parse(literal)
+	default:
+		parser.throw(tok.Start, "expected a type")
+		return parser.synchronize()
+	}
+	for tok := parser.peek(); tok.is(SymbolToken, "?"); tok = parser.peek() {
+		parser.next()
+		ty = Span{ty.start, tok.End, Unary{Maybe, ty}}
+	}
+	return ty
+}
+
+func (parser *parser) parse(prec precedence) (left Span) {
+	tok := parser.peek()
 	switch {
-	case unicode.IsDigit(start.peek()) && prec <= literal:
-		end := start.next()
-		for unicode.IsDigit(end.peek()) {
-			end = end.next()
+	case tok.Kind == IntegerToken && prec <= literal:
+		parser.next()
+		left = Span{tok.Start, tok.End, IntegerLiteral{tok.Text}}
+
+	case tok.is(SymbolToken, "(") && prec <= bracket:
+		left = parser.parseBracket(")", tuple)
+		if prec == bracket {
+			return left
 		}
-		left = Span{start, end, IntegerLiteral{between(start, end)}}
 
-	case start.peek() == '(' && prec <= bracket:
-		return parser.parseBracket(start, ')', tuple)
+	case tok.is(SymbolToken, "{") && prec <= bracket:
+		left = parser.parseBracket("}", block)
+		if prec == bracket {
+			return left
+		}
 
-	case start.peek() == '{' && prec <= bracket:
-		return parser.parseBracket(start, '}', block)
+	case tok.is(SymbolToken, "[") && prec <= bracket:
+		left = parser.parseArray()
 
-	case unicode.IsLetter(start.peek()) && prec <= literal:
-		end := start.next()
-		for unicode.IsLetter(end.peek()) || unicode.IsDigit(end.peek()) {
-			end = end.next()
+	case tok.Kind == StringToken && prec <= literal:
+		parser.next()
+		if _, err := Unquote(tok.Text); err != nil {
+			parser.throw(tok.Start, err.Error())
 		}
-		switch between(start, end) {
+		left = Span{tok.Start, tok.End, StringLiteral{tok.Text}}
+
+	case tok.Kind == CharToken && prec <= literal:
+		parser.next()
+		if value, err := Unquote(tok.Text); err != nil {
+			parser.throw(tok.Start, err.Error())
+		} else if len(value) != 1 {
+			parser.throw(tok.Start, "char literal must contain exactly one byte")
+		}
+		left = Span{tok.Start, tok.End, CharLiteral{tok.Text}}
+
+	case tok.Kind == IdentToken && prec <= literal:
+		parser.next()
+		left = Span{tok.Start, tok.End, Identifier{tok.Text}}
+
+	case tok.Kind == KeywordToken && prec <= literal:
+		switch tok.Text {
 
 		case "if":
-			cond := parser.parse(end, bracket)
-			conc := parser.parse(cond.end, expr)
-			left = Span{start, conc.end, newBinary(cond, conc, If)}
+			parser.next()
+			cond := parser.parse(bracket)
+			conc := parser.parse(expr)
+			left = Span{tok.Start, conc.end, newBinary(cond, conc, If)}
 
 		case "while":
-			cond := parser.parse(end, bracket)
-			body := parser.parse(cond.end, expr)
-			left = Span{start, body.end, newBinary(cond, body, While)}
+			parser.next()
+			cond := parser.parse(bracket)
+			body := parser.parse(expr)
+			left = Span{tok.Start, body.end, newBinary(cond, body, While)}
 
 		case "struct":
-			block := parser.parse(end, bracket)
-			left = Span{start, block.end, Struct{block}}
+			parser.next()
+			block := parser.parse(bracket)
+			left = Span{tok.Start, block.end, Struct{block}}
 
 		case "fn":
-			param := parser.parse(end, bracket)
-			body := parser.parse(param.end, expr)
-			left = Span{start, body.end, newBinary(param, body, Func)}
+			parser.next()
+			param := parser.parse(bracket)
+			if arrow := parser.peek(); arrow.is(SymbolToken, "->") {
+				parser.next()
+				param = newInfix(param, parser.parseType(), Returns)
+			}
+			body := parser.parse(expr)
+			left = Span{tok.Start, body.end, newBinary(param, body, Func)}
+
+		case "mmio":
+			parser.next()
+			ty := parser.peek()
+			if ty.Kind != IdentToken {
+				parser.throw(ty.Start, "expected a register type after 'mmio'")
+				return parser.synchronize()
+			}
+			parser.next()
+			if !parser.peek().is(IdentToken, "at") {
+				parser.throw(parser.peek().Start, "expected 'at' after the register type")
+				return parser.synchronize()
+			}
+			parser.next()
+			address := parser.parse(literal)
+			left = Span{tok.Start, address.end, Mmio{ty.Text, address}}
+
+		case "enum":
+			parser.next()
+			block := parser.parse(bracket)
+			left = Span{tok.Start, block.end, Enum{block}}
+
+		case "match":
+			parser.next()
+			value := parser.parse(bracket)
+			if !parser.peek().is(SymbolToken, "{") {
+				parser.throw(parser.peek().Start, "expected '{' before the arms of 'match'")
+				return parser.synchronize()
+			}
+			block := parser.parse(bracket)
+			left = Span{tok.Start, block.end, Match{value, block}}
+
+		case "import":
+			parser.next()
+			path := parser.peek()
+			if path.Kind != StringToken {
+				parser.throw(path.Start, "expected a module path after 'import'")
+				return parser.synchronize()
+			}
+			parser.next()
+			left = Span{tok.Start, path.End, Import{path.Text}}
+			if _, err := left.expr.(Import).Module(); err != nil {
+				parser.throw(path.Start, err.Error())
+			}
+
+		case "return":
+			parser.next()
+			value := parser.parse(expr)
+			left = Span{tok.Start, value.end, Return{value}}
+
+		case "break":
+			parser.next()
+			left = Span{tok.Start, tok.End, Break{}}
+
+		case "continue":
+			parser.next()
+			left = Span{tok.Start, tok.End, Continue{}}
 
 		case "true", "false":
-			left = Span{start, end, BooleanLiteral{between(start, end)}}
+			parser.next()
+			left = Span{tok.Start, tok.End, BooleanLiteral{tok.Text}}
 
 		default:
-			left = Span{start, end, Identifier{between(start, end)}}
+			parser.throw(tok.Start, "no value")
+			return parser.synchronize()
 		}
+
+	case tok.Kind == InvalidToken && (strings.HasPrefix(tok.Text, "\"") || strings.HasPrefix(tok.Text, "'")):
+		parser.throw(tok.Start, "unterminated literal")
+		return parser.synchronize()
+
 	default:
-		parser.throw(start, "no value")
-		return Span{start, start.next(), nil}
+		parser.throw(tok.Start, "no value")
+		return parser.synchronize()
 	}
 
 	for {
-		start := skipSpaces(left.end)
-		keywordEnd := start
-		for unicode.IsLetter(keywordEnd.peek()) {
-			keywordEnd = keywordEnd.next()
-		}
+		tok := parser.peek()
 
 		switch {
-		case start.peek() == '+' && prec <= addition:
-			left = newInfix(left, parser.parse(start.next(), addition), Add)
+		case tok.is(SymbolToken, "+") && prec <= addition:
+			parser.next()
+			left = newInfix(left, parser.parse(addition), Add)
+
+		case tok.is(SymbolToken, "?") && prec <= expr:
+			parser.next()
+			left = Span{left.start, tok.End, Unary{Maybe, left}}
+
+		case tok.is(SymbolToken, ".") && prec <= dot:
+			parser.next()
+			left = newInfix(left, parser.parse(dot), Dot)
+
+		case tok.is(SymbolToken, ":") && prec <= annotation:
+			parser.next()
+			left = newInfix(left, parser.parseType(), Annotation)
 
-		case start.peek() == '?' && prec <= expr:
-			left = Span{left.start, start.next(), Unary{Maybe, left}}
+		case tok.is(SymbolToken, "->") && prec <= annotation:
+			parser.next()
+			left = newInfix(left, parser.parse(annotation), Arm)
 
-		case start.peek() == '.' && prec <= dot:
-			left = newInfix(left, parser.parse(start.next(), dot), Dot)
+		case tok.is(SymbolToken, "=") && prec <= statement:
+			parser.next()
+			left = newInfix(left, parser.parse(statement), SingleEquals)
 
-		case start.peek() == '=' && prec <= statement:
-			left = newInfix(left, parser.parse(start.next(), statement), SingleEquals)
+		case tok.is(SymbolToken, "<") && prec <= comparison:
+			parser.next()
+			left = newInfix(left, parser.parse(comparison), LessThan)
 
-		case start.peek() == '<' && prec <= comparison:
-			left = newInfix(left, parser.parse(start.next(), comparison), LessThan)
+		case tok.is(KeywordToken, "else") && prec < expr:
+			parser.next()
+			left = newInfix(left, parser.parse(expr), Else)
 
-		case between(start, keywordEnd) == "else" && prec < expr:
-			left = newInfix(left, parser.parse(keywordEnd, expr), Else)
+		case tok.is(SymbolToken, "(") && tok.Start.line == left.end.line && prec <= bracket:
+			left = newInfix(left, parser.parse(bracket), Call)
 
-		case start.peek() == '(' && prec <= bracket:
-			left = newInfix(left, parser.parse(start, bracket), Call)
+		case tok.is(SymbolToken, "[") && tok.Start.line == left.end.line && prec <= indicies:
+			parser.next()
+			index := parser.parse(tuple)
+			end := index.end
+			if parser.peek().is(SymbolToken, "]") {
+				end = parser.next().End
+			} else {
+				parser.throw(parser.peek().Start, "missing close bracket")
+			}
+			left = Span{left.start, end, Binary{Index, left, index}}
 
-		case start.peek() == ',' && prec <= tuple:
-			left = newTuple(left, parser.parse(start.next(), tuple))
+		case tok.is(SymbolToken, ",") && prec <= tuple:
+			parser.next()
+			left = newTuple(left, parser.parse(tuple))
 
-		case start.peek() != ')' && start.peek() != '}' && start.len() > 0 && prec <= block:
-			left = newBlock(left, parser.parse(start, statement))
+		case !parser.isClose(tok) && prec <= block:
+			left = newBlock(left, parser.parse(statement))
 
 		default:
 			return left
@@ -0,0 +1,583 @@
+_start:
+  mov $200, %rcx
+  mov $44, %rdx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $5, %rbx
+  mov $1, %rdx
+  mov $1, %rsi
+  cmp %rsi, %rdx
+  je b1
+b2:
+b3:
+  mov $5, %rsi
+  mov $1, %rdx
+  mov $1, %rbx
+  cmp %rbx, %rdx
+  je b4
+b5:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b6:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $250, %rdx
+  cmp %rcx, %rdx
+  jg b7
+b8:
+  mov $0, %rcx
+b9:
+  mov $1, %rdx
+  cmp %rdx, %rcx
+  je b10
+b11:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text3(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b12:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text4(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rcx
+  mov $2, %rax
+  call b13
+b14:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text5(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $7, %rcx
+  mov $1, %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text6(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text7(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rcx
+  cmp %rcx, %rax
+  je b15
+b16:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text8(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b17:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text9(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rax
+  call b18
+b19:
+  mov $1, %rdx
+  cmp %rdx, %rcx
+  je b20
+b21:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text10(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b22:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text11(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $3, %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text12(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rax, %rdi
+  mov $60, %eax
+  syscall
+b20:
+  mov $1, %rcx
+  cmp %rcx, %rax
+  je b23
+b24:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text13(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b25:
+  jmp b22
+b23:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text14(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b25
+b18:
+  push %rbp
+  mov %rsp, %rbp
+  mov $1, %rcx
+  leave
+  ret
+b15:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text15(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b17
+b13:
+  push %rbp
+  mov %rsp, %rbp
+  add %rax, %rcx
+  leave
+  ret
+b10:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b12
+b7:
+  mov $300, %rax
+  mov $1, %rcx
+  jmp b9
+b4:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rsi, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b6
+b1:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rbx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text16(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b3
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
+text1:
+  .ascii "none"
+text2:
+  .ascii "\012"
+text3:
+  .ascii "none"
+text4:
+  .ascii "\012"
+text5:
+  .ascii "\012"
+text6:
+  .ascii "("
+text7:
+  .ascii ", "
+text8:
+  .ascii "false"
+text9:
+  .ascii ")\012"
+text10:
+  .ascii "none"
+text11:
+  .ascii "\012"
+text12:
+  .ascii "\012"
+text13:
+  .ascii "false"
+text14:
+  .ascii "true"
+text15:
+  .ascii "true"
+text16:
+  .ascii "\012"
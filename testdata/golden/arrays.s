@@ -0,0 +1,934 @@
+_start:
+  mov %rsp, %rbp
+  sub $160, %rsp
+  mov $2, %rcx
+  mov $3, %r8
+  mov $5, %rsi
+  mov $7, %rdi
+  mov $11, %rdx
+  lea -40(%rbp), %rax
+  mov $0, %rbx
+  mov %rcx, (%rax,%rbx,8)
+  mov $1, %rcx
+  mov %r8, (%rax,%rcx,8)
+  mov $2, %rcx
+  mov %rsi, (%rax,%rcx,8)
+  mov $3, %rcx
+  mov %rdi, (%rax,%rcx,8)
+  mov $4, %rcx
+  mov %rdx, (%rax,%rcx,8)
+  mov $0, %rdx
+  mov $0, %rcx
+  mov $5, %rbx
+  cmp %rdx, %rbx
+  jg b1
+b5:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $13, %rdx
+  mov $0, %rcx
+  mov $5, %rbx
+  cmp %rcx, %rbx
+  jg b12
+b14:
+  ud2
+b12:
+  mov $0, %rbx
+  cmp %rcx, %rbx
+  jle b13
+  jmp b14
+b13:
+  mov %rdx, (%rax,%rcx,8)
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rcx
+  mov (%rax,%rcx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rcx
+  mov (%rax,%rcx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text3(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $2, %rcx
+  mov (%rax,%rcx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text4(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $3, %rcx
+  mov (%rax,%rcx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text5(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $4, %rcx
+  mov (%rax,%rcx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text6(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdi
+  mov $1, %rdx
+  mov $2, %rbx
+  mov $0, %rsi
+  lea -72(%rbp), %rcx
+  mov $0, %r8
+  mov %rdi, (%rcx,%r8,8)
+  mov $1, %rdi
+  mov %rdx, (%rcx,%rdi,8)
+  mov $2, %rdx
+  mov %rbx, (%rcx,%rdx,8)
+  mov $3, %rdx
+  mov %rsi, (%rcx,%rdx,8)
+  mov $3, %rdx
+  mov $1, %rbx
+  mov $1, %rdi
+  mov $2, %rsi
+  cmp %rdi, %rsi
+  jg b15
+b17:
+  ud2
+b15:
+  mov $0, %rsi
+  cmp %rdi, %rsi
+  jle b16
+  jmp b17
+b16:
+  mov $2, %rsi
+  mov %rdx, (%rcx,%rsi,8)
+  mov $3, %rdx
+  mov %rbx, (%rcx,%rdx,8)
+  mov $1, %rdx
+  mov $2, %rbx
+  cmp %rdx, %rbx
+  jg b18
+b20:
+  ud2
+b18:
+  mov $0, %rbx
+  cmp %rdx, %rbx
+  jle b19
+  jmp b20
+b19:
+  mov $2, %rdx
+  mov (%rcx,%rdx,8), %rdx
+  mov $3, %rbx
+  mov (%rcx,%rbx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text7(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text8(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdx
+  cmp %rdx, %rcx
+  je b21
+b22:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text9(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b23:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text10(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rsi
+  mov $2, %rcx
+  lea -88(%rbp), %rbx
+  mov $0, %rdx
+  mov %rsi, (%rbx,%rdx,8)
+  mov $1, %rdx
+  mov %rcx, (%rbx,%rdx,8)
+  mov $3, %rsi
+  mov $4, %rdx
+  lea -104(%rbp), %rcx
+  mov $0, %rdi
+  mov %rsi, (%rcx,%rdi,8)
+  mov $1, %rsi
+  mov %rdx, (%rcx,%rsi,8)
+  lea -120(%rbp), %rdx
+  mov $0, %rsi
+  mov %rbx, (%rdx,%rsi,8)
+  mov $1, %rbx
+  mov %rcx, (%rdx,%rbx,8)
+  mov $1, %rcx
+  mov $2, %rbx
+  cmp %rcx, %rbx
+  jg b24
+b26:
+  ud2
+b24:
+  mov $0, %rbx
+  cmp %rcx, %rbx
+  jle b25
+  jmp b26
+b25:
+  mov (%rdx,%rcx,8), %rcx
+  mov $9, %rbx
+  mov $0, %rdi
+  mov $2, %rsi
+  cmp %rdi, %rsi
+  jg b27
+b29:
+  ud2
+b27:
+  mov $0, %rsi
+  cmp %rdi, %rsi
+  jle b28
+  jmp b29
+b28:
+  mov %rbx, (%rcx,%rdi,8)
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text11(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rcx
+  mov (%rdx,%rcx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text12(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rbx
+  mov (%rcx,%rbx,8), %rbx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rbx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text13(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rbx
+  mov (%rcx,%rbx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text14(%rip), %rsi
+  mov $3, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rcx
+  mov (%rdx,%rcx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text15(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rdx
+  mov (%rcx,%rdx,8), %rdx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text16(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdx
+  mov (%rcx,%rdx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text17(%rip), %rsi
+  mov $3, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  lea data0(%rip), %rdi
+  mov $1, %rsi
+  lea data1(%rip), %rdx
+  mov $2, %rcx
+  lea -152(%rbp), %rbx
+  mov $0, %r8
+  mov %rdi, (%rbx,%r8,8)
+  mov $1, %rdi
+  mov %rsi, (%rbx,%rdi,8)
+  mov $2, %rsi
+  mov %rdx, (%rbx,%rsi,8)
+  mov $3, %rdx
+  mov %rcx, (%rbx,%rdx,8)
+  mov $1, %rcx
+  mov $2, %rdx
+  cmp %rcx, %rdx
+  jg b30
+b32:
+  ud2
+b30:
+  mov $0, %rdx
+  cmp %rcx, %rdx
+  jle b31
+  jmp b32
+b31:
+  mov $2, %rcx
+  mov (%rbx,%rcx,8), %rcx
+  mov $3, %rdx
+  mov (%rbx,%rdx,8), %rdx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  push %rcx
+  push %rdx
+  pop %rdx
+  pop %rsi
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $10, %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  sub $8, %rsp
+  mov %rcx, (%rsp)
+  mov $1, %eax
+  mov $1, %edi
+  mov %rsp, %rsi
+  mov $1, %edx
+  syscall
+  add $8, %rsp
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $4, %rcx
+  mov $5, %rdx
+  cmp %rcx, %rdx
+  jg b33
+b35:
+  ud2
+b33:
+  mov $0, %rdx
+  cmp %rcx, %rdx
+  jle b34
+  jmp b35
+b34:
+  mov (%rax,%rcx,8), %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text18(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rax, %rdi
+  mov $60, %eax
+  syscall
+b21:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text19(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b23
+b1:
+  mov $5, %rbx
+  cmp %rdx, %rbx
+  jg b6
+b8:
+  ud2
+b6:
+  mov $0, %rbx
+  cmp %rdx, %rbx
+  jle b7
+  jmp b8
+b7:
+  mov (%rax,%rdx,8), %rdx
+  add %rdx, %rcx
+  mov $1, %rdx
+b2:
+  mov $5, %rbx
+  cmp %rdx, %rbx
+  jg b3
+b4:
+  jmp b5
+b3:
+  mov $5, %rbx
+  cmp %rdx, %rbx
+  jg b9
+b11:
+  ud2
+b9:
+  mov $0, %rbx
+  cmp %rdx, %rbx
+  jle b10
+  jmp b11
+b10:
+  mov (%rax,%rdx,8), %rbx
+  add %rbx, %rcx
+  mov $1, %rbx
+  add %rbx, %rdx
+  jmp b2
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
+text1:
+  .ascii "["
+text2:
+  .ascii ", "
+text3:
+  .ascii ", "
+text4:
+  .ascii ", "
+text5:
+  .ascii ", "
+text6:
+  .ascii "]\012"
+text7:
+  .ascii "("
+text8:
+  .ascii ", "
+text9:
+  .ascii "false"
+text10:
+  .ascii ")\012"
+text11:
+  .ascii "["
+text12:
+  .ascii "["
+text13:
+  .ascii ", "
+text14:
+  .ascii "], "
+text15:
+  .ascii "["
+text16:
+  .ascii ", "
+text17:
+  .ascii "]]\012"
+text18:
+  .ascii "\012"
+text19:
+  .ascii "true"
+data0:
+  .ascii "a"
+data1:
+  .ascii "bc"
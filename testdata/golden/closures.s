@@ -0,0 +1,517 @@
+_start:
+  mov $1, %rax
+  push %r8
+  call b1
+  pop %r8
+b2:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $100, %rbx
+  mov $0, %rdi
+  mov $1, %rax
+  push %rbx
+  push %rdi
+  push %r8
+  call b3
+  pop %r8
+  pop %rdi
+  pop %rbx
+b4:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rcx
+  mov $0, %rsi
+  mov $3, %rax
+  cmp %rcx, %rax
+  jg b5
+b9:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rsi, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $2, %rax
+  push %rdi
+  push %r8
+  call b14
+  pop %r8
+  pop %rdi
+b17:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text3(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rcx
+  push %rdi
+  push %r8
+  call b18
+  pop %r8
+  pop %rdi
+b19:
+  mov $2, %rax
+  push %rdi
+  push %r8
+  call b20
+  pop %r8
+  pop %rdi
+b21:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text4(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $3, %rax
+  push %rdi
+  push %r8
+  call b22
+  pop %r8
+  pop %rdi
+b25:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text5(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rcx
+  mov %rdi, %rax
+  call b26
+b34:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %r8, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text6(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %r8, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text7(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %r8, %rdi
+  mov $60, %eax
+  syscall
+b26:
+  push %rbp
+  mov %rsp, %rbp
+  mov %rcx, %rdx
+  mov $3, %rcx
+  add %rcx, %rax
+  cmp %rdx, %rax
+  jg b27
+b28:
+  mov $0, %rcx
+b29:
+  mov $0, %rax
+  cmp %rax, %rcx
+  je b31
+b32:
+  mov %r8, %rdx
+b33:
+  mov %rdx, %r8
+  leave
+  ret
+b31:
+  jmp b33
+b27:
+  mov $1, %rcx
+  add %rdx, %rcx
+  mov %rdi, %rax
+  push %rdx
+  call b26
+  pop %rdx
+b30:
+  mov $1, %rcx
+  jmp b29
+b22:
+  push %rbp
+  mov %rsp, %rbp
+  call b23
+b24:
+  leave
+  ret
+b23:
+  push %rbp
+  mov %rsp, %rbp
+  add %rax, %rax
+  leave
+  ret
+b20:
+  push %rbp
+  mov %rsp, %rbp
+  add %rax, %rcx
+  leave
+  ret
+b18:
+  push %rbp
+  mov %rsp, %rbp
+  mov $1, %rdx
+  add %rdx, %rcx
+  leave
+  ret
+b14:
+  push %rbp
+  mov %rsp, %rbp
+  push %rbx
+  call b3
+  pop %rbx
+b15:
+  call b3
+b16:
+  leave
+  ret
+b3:
+  push %rbp
+  mov %rsp, %rbp
+  add %rbx, %rax
+  leave
+  ret
+b5:
+  mov $1, %rdx
+  mov $10, %rax
+  push %rdx
+  push %rbx
+  push %rsi
+  push %rdi
+  push %r8
+  call b10
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rbx
+  pop %rdx
+b11:
+  add %rax, %rsi
+b6:
+  mov $3, %rax
+  cmp %rdx, %rax
+  jg b7
+b8:
+  jmp b9
+b7:
+  mov %rdx, %rcx
+  mov $1, %rax
+  add %rax, %rdx
+  mov $10, %rax
+  push %rdx
+  push %rbx
+  push %rsi
+  push %rdi
+  push %r8
+  call b12
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rbx
+  pop %rdx
+b13:
+  add %rax, %rsi
+  jmp b6
+b12:
+  push %rbp
+  mov %rsp, %rbp
+  add %rcx, %rax
+  leave
+  ret
+b10:
+  push %rbp
+  mov %rsp, %rbp
+  add %rcx, %rax
+  leave
+  ret
+b1:
+  push %rbp
+  mov %rsp, %rbp
+  leave
+  ret
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
+text1:
+  .ascii "\012"
+text2:
+  .ascii "\012"
+text3:
+  .ascii "\012"
+text4:
+  .ascii "\012"
+text5:
+  .ascii "\012"
+text6:
+  .ascii "\012"
+text7:
+  .ascii "\012"
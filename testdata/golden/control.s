@@ -0,0 +1,364 @@
+_start:
+  mov $0, %rcx
+  mov $0, %rdx
+  mov $10, %rax
+  mov %rcx, %rbx
+  cmp %rcx, %rax
+  jg b1
+b5:
+b23:
+b24:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rbx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdx
+  call b25
+b44:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $8, %rdx
+  call b25
+b45:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text3(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $7, %rdx
+  call b25
+b46:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text4(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text5(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rdx, %rdi
+  mov $60, %eax
+  syscall
+b25:
+  push %rbp
+  mov %rsp, %rbp
+  mov $3, %rax
+  cmp %rdx, %rax
+  jg b26
+b27:
+b28:
+  mov $0, %rax
+  cmp %rax, %rdx
+  jg b30
+b34:
+b43:
+  leave
+  ret
+b30:
+  mov $1, %rcx
+  cmp %rdx, %rcx
+  jg b35
+b36:
+b37:
+  mov $1, %rax
+b31:
+  cmp %rax, %rdx
+  jg b32
+b33:
+  jmp b34
+b32:
+  mov $1, %rcx
+  add %rax, %rcx
+  add %rax, %rcx
+  cmp %rdx, %rcx
+  jg b39
+b40:
+b41:
+  mov $1, %rcx
+  add %rcx, %rax
+  jmp b31
+b39:
+  mov %rax, %rdx
+  jmp b43
+b35:
+  mov %rax, %rdx
+  jmp b43
+b26:
+  mov $0, %rdx
+  jmp b43
+b1:
+  mov $1, %rbx
+  mov $3, %rax
+  cmp %rax, %rbx
+  je b8
+b6:
+  mov $8, %rax
+  cmp %rax, %rbx
+  je b12
+b10:
+b11:
+b7:
+  mov $1, %rdx
+b14:
+b2:
+  mov $10, %rax
+  cmp %rbx, %rax
+  jg b3
+b4:
+  jmp b5
+b3:
+  mov $1, %rax
+  add %rax, %rbx
+  mov $3, %rax
+  cmp %rax, %rbx
+  je b17
+b15:
+  mov $8, %rax
+  cmp %rax, %rbx
+  je b21
+b19:
+b20:
+b16:
+  add %rbx, %rdx
+  jmp b2
+b21:
+  jmp b24
+b17:
+  jmp b2
+b12:
+  jmp b23
+b8:
+  jmp b14
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
+text1:
+  .ascii "\012"
+text2:
+  .ascii "\012"
+text3:
+  .ascii "\012"
+text4:
+  .ascii "\012"
+text5:
+  .ascii "\012"
@@ -0,0 +1,187 @@
+_start:
+  mov %rsp, %rbp
+  sub $16, %rsp
+  mov $10, %rcx
+  mov %rcx, %rdx
+  mov $5, %rax
+  cmp %rcx, %rax
+  jg b3
+b4:
+  mov $0, %rax
+b5:
+  mov $0, %rcx
+  cmp %rcx, %rax
+  je b6
+b7:
+  lea b9(%rip), %rax
+  mov $0, %rbx
+b8:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $16, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rcx
+  call *%rax
+b13:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rcx, %rdi
+  mov $60, %eax
+  syscall
+b11:
+  push %rbp
+  mov %rsp, %rbp
+  mov $0, %rax
+  mov (%rbx,%rax,8), %rax
+  call b2
+b12:
+  leave
+  ret
+b2:
+  push %rbp
+  mov %rsp, %rbp
+  add %rax, %rcx
+  leave
+  ret
+b9:
+  push %rbp
+  mov %rsp, %rbp
+  call b1
+b10:
+  leave
+  ret
+b1:
+  push %rbp
+  mov %rsp, %rbp
+  mov $1, %rax
+  add %rax, %rcx
+  leave
+  ret
+b6:
+  lea b11(%rip), %rax
+  lea -8(%rbp), %rbx
+  mov $0, %rcx
+  mov %rdx, (%rbx,%rcx,8)
+  jmp b8
+b3:
+  mov $1, %rax
+  jmp b5
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "fn (int) -> int\012"
+text1:
+  .ascii "\012"
+text2:
+  .ascii "\012"
@@ -0,0 +1,99 @@
+_start:
+  mov $1, %rcx
+  mov $2, %rax
+  cmp %rcx, %rax
+  jg b1
+b2:
+  mov $0, %rax
+b3:
+  mov $1, %rcx
+  cmp %rcx, %rax
+  je b4
+b5:
+  mov $0, %rcx
+b6:
+  mov $0, %rax
+  cmp %rax, %rcx
+  je b7
+b8:
+b9:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rdx, %rdi
+  mov $60, %eax
+  syscall
+b7:
+  mov $0, %rdx
+  jmp b9
+b4:
+  mov $6, %rdx
+  mov $1, %rcx
+  jmp b6
+b1:
+  mov $1, %rax
+  jmp b3
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
@@ -0,0 +1,600 @@
+_start:
+  mov %rsp, %rbp
+  sub $48, %rsp
+  lea data0(%rip), %rax
+  mov $16, %rdi
+  mov $0, %rcx
+  mov $16, %rdx
+  cmp %rcx, %rdx
+  jg b1
+b5:
+  mov $10, %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  sub $8, %rsp
+  mov %rcx, (%rsp)
+  mov $1, %eax
+  mov $1, %edi
+  mov %rsp, %rsi
+  mov $1, %edx
+  syscall
+  add $8, %rsp
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rsi
+  mov $0, %rdx
+  lea -16(%rbp), %rcx
+  mov $0, %rbx
+  mov %rsi, (%rcx,%rbx,8)
+  mov $1, %rbx
+  mov %rdx, (%rcx,%rbx,8)
+  mov $1, %rdx
+  mov $1, %rbx
+  mov $2, %rsi
+  cmp %rbx, %rsi
+  jg b12
+b14:
+  ud2
+b12:
+  mov $0, %rsi
+  cmp %rbx, %rsi
+  jle b13
+  jmp b14
+b13:
+  mov %rdx, (%rcx,%rbx,8)
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rdx
+  mov (%rcx,%rdx,8), %rbx
+  mov $1, %rdx
+  cmp %rdx, %rbx
+  je b15
+b16:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b17:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdx
+  mov (%rcx,%rdx,8), %rcx
+  mov $1, %rdx
+  cmp %rdx, %rcx
+  je b18
+b19:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text3(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b20:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text4(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rsi
+  mov $0, %r8
+  mov $0, %rdx
+  lea -40(%rbp), %rcx
+  mov $0, %rbx
+  mov %rsi, (%rcx,%rbx,8)
+  mov $1, %rbx
+  mov %r8, (%rcx,%rbx,8)
+  mov $2, %rbx
+  mov %rdx, (%rcx,%rbx,8)
+  mov $0, %rdx
+  mov $3, %rbx
+  cmp %rdx, %rbx
+  jg b21
+b25:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text5(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rdx
+  mov (%rcx,%rdx,8), %rdx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text6(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdx
+  mov (%rcx,%rdx,8), %rdx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text7(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $2, %rdx
+  mov (%rcx,%rdx,8), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rcx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text8(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $15, %rcx
+  cmp %rcx, %rdi
+  jg b38
+b40:
+  ud2
+b38:
+  mov $0, %rdx
+  cmp %rcx, %rdx
+  jle b39
+  jmp b40
+b39:
+  movzbq (%rax,%rcx,1), %rax
+  add %rax, %rdi
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdi, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text9(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rdi, %rdi
+  mov $60, %eax
+  syscall
+b21:
+  mov $3, %rbx
+  cmp %rdx, %rbx
+  jg b26
+b28:
+  ud2
+b26:
+  mov $0, %rbx
+  cmp %rdx, %rbx
+  jle b27
+  jmp b28
+b27:
+  mov (%rcx,%rdx,8), %rbx
+  mov $1, %rsi
+  add %rsi, %rbx
+  mov $3, %rsi
+  cmp %rdx, %rsi
+  jg b29
+b31:
+  ud2
+b29:
+  mov $0, %rsi
+  cmp %rdx, %rsi
+  jle b30
+  jmp b31
+b30:
+  mov %rbx, (%rcx,%rdx,8)
+  mov $1, %rdx
+b22:
+  mov $3, %rbx
+  cmp %rdx, %rbx
+  jg b23
+b24:
+  jmp b25
+b23:
+  mov $3, %rbx
+  cmp %rdx, %rbx
+  jg b32
+b34:
+  ud2
+b32:
+  mov $0, %rbx
+  cmp %rdx, %rbx
+  jle b33
+  jmp b34
+b33:
+  mov (%rcx,%rdx,8), %rsi
+  mov $1, %rbx
+  add %rdx, %rbx
+  add %rbx, %rsi
+  mov $3, %rbx
+  cmp %rdx, %rbx
+  jg b35
+b37:
+  ud2
+b35:
+  mov $0, %rbx
+  cmp %rdx, %rbx
+  jle b36
+  jmp b37
+b36:
+  mov %rsi, (%rcx,%rdx,8)
+  mov $1, %rbx
+  add %rbx, %rdx
+  jmp b22
+b18:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text10(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b20
+b15:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text11(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b17
+b1:
+  cmp %rcx, %rdi
+  jg b6
+b8:
+  ud2
+b6:
+  mov $0, %rdx
+  cmp %rcx, %rdx
+  jle b7
+  jmp b8
+b7:
+  movzbq (%rax,%rcx,1), %rcx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  sub $8, %rsp
+  mov %rcx, (%rsp)
+  mov $1, %eax
+  mov $1, %edi
+  mov %rsp, %rsi
+  mov $1, %edx
+  syscall
+  add $8, %rsp
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $5, %rcx
+b2:
+  mov $16, %rdx
+  cmp %rcx, %rdx
+  jg b3
+b4:
+  jmp b5
+b3:
+  cmp %rcx, %rdi
+  jg b9
+b11:
+  ud2
+b9:
+  mov $0, %rdx
+  cmp %rcx, %rdx
+  jle b10
+  jmp b11
+b10:
+  movzbq (%rax,%rcx,1), %rdx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  sub $8, %rsp
+  mov %rdx, (%rsp)
+  mov $1, %eax
+  mov $1, %edi
+  mov %rsp, %rsi
+  mov $1, %edx
+  syscall
+  add $8, %rsp
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $5, %rdx
+  add %rdx, %rcx
+  jmp b2
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "["
+text1:
+  .ascii "false"
+text2:
+  .ascii ", "
+text3:
+  .ascii "false"
+text4:
+  .ascii "]\012"
+text5:
+  .ascii "["
+text6:
+  .ascii ", "
+text7:
+  .ascii ", "
+text8:
+  .ascii "]\012"
+text9:
+  .ascii "\012"
+text10:
+  .ascii "true"
+text11:
+  .ascii "true"
+data0:
+  .ascii "0123456789abcdef"
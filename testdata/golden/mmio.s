@@ -0,0 +1,106 @@
+_start:
+  mov $511, %rax
+  mov $1073876992, %rcx
+  mov $0, %rdx
+  mov %eax, (%rcx,%rdx,4)
+  mov $1073876992, %rcx
+  mov $0, %rax
+  mov (%rcx,%rax,4), %edx
+  mov $1, %rax
+  add %rax, %rdx
+  mov %edx, %edx
+  mov $1073876992, %rcx
+  mov $0, %rax
+  mov %edx, (%rcx,%rax,4)
+  mov $200, %rdx
+  mov $1073876996, %rax
+  mov $0, %rcx
+  mov %dl, (%rax,%rcx,1)
+  mov $1073876996, %rcx
+  mov $0, %rax
+  movzbq (%rcx,%rax,1), %rax
+  mov $1073876992, %rax
+  mov $0, %rcx
+  mov (%rax,%rcx,4), %edx
+  mov $1073876996, %rcx
+  mov $0, %rax
+  movzbq (%rcx,%rax,1), %rax
+  mov %eax, %eax
+  add %rax, %rdx
+  mov %edx, %edx
+  mov $1073876992, %rax
+  mov $0, %rcx
+  mov %edx, (%rax,%rcx,4)
+  mov $1073876992, %rax
+  mov $0, %rcx
+  mov (%rax,%rcx,4), %eax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rax, %rdi
+  mov $60, %eax
+  syscall
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
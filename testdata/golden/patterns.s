@@ -0,0 +1,346 @@
+_start:
+  mov $3, %rbx
+  mov $4, %rcx
+  mov $5, %rax
+  mov $7, %rdx
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rdx
+  push %rax
+  push %rcx
+  push %rbx
+  call b1
+  pop %rbx
+  pop %rcx
+  pop %rax
+b8:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  push %rsi
+  push %rdx
+  pop %rdx
+  pop %rsi
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdx
+  push %rax
+  push %rcx
+  push %rbx
+  call b1
+  pop %rbx
+  pop %rcx
+  pop %rax
+b9:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  push %rsi
+  push %rdx
+  pop %rdx
+  pop %rsi
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $7, %rdx
+  push %rax
+  push %rcx
+  push %rbx
+  call b1
+  pop %rbx
+  pop %rcx
+  pop %rax
+b10:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  push %rsi
+  push %rdx
+  pop %rdx
+  pop %rsi
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  cmp %rbx, %rcx
+  jg b11
+b12:
+  mov $0, %rdx
+b13:
+  mov $1, %rsi
+  cmp %rsi, %rdx
+  je b16
+b14:
+  mov $1, %rsi
+  cmp %rsi, %rdx
+  je b20
+b18:
+  mov $0, %rdx
+b19:
+b15:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  call b21
+b22:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rbx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rbx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text3(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov %rbx, %rdi
+  mov $60, %eax
+  syscall
+b21:
+  push %rbp
+  mov %rsp, %rbp
+  add %rax, %rcx
+  add %rcx, %rbx
+  leave
+  ret
+b20:
+  mov %rbx, %rdx
+  jmp b19
+b16:
+  mov $3, %rsi
+  cmp %rsi, %rbx
+  je b17
+  jmp b14
+b17:
+  mov $30, %rdx
+  jmp b15
+b11:
+  mov $1, %rdx
+  jmp b13
+b1:
+  push %rbp
+  mov %rsp, %rbp
+  mov $0, %rax
+  cmp %rax, %rdx
+  je b4
+b2:
+  mov $1, %rax
+  cmp %rax, %rdx
+  je b7
+b5:
+  lea data2(%rip), %rsi
+  mov $4, %rdx
+b6:
+b3:
+  leave
+  ret
+b7:
+  lea data1(%rip), %rsi
+  mov $3, %rdx
+  jmp b6
+b4:
+  lea data0(%rip), %rsi
+  mov $4, %rdx
+  jmp b3
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
+text1:
+  .ascii "\012"
+text2:
+  .ascii "\012"
+text3:
+  .ascii "\012"
+data0:
+  .ascii "zero"
+data1:
+  .ascii "one"
+data2:
+  .ascii "many"
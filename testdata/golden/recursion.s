@@ -0,0 +1,851 @@
+_start:
+  mov $0, %rax
+  push %rcx
+  push %rbx
+  push %rsi
+  push %r8
+  call b1
+  pop %r8
+  pop %rsi
+  pop %rbx
+  pop %rcx
+b9:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rdx, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text0(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rdi
+  mov $1, %rdx
+  mov $0, %rax
+  push %rcx
+  push %rbx
+  push %rsi
+  call b10
+  pop %rsi
+  pop %rbx
+  pop %rcx
+b18:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %r8, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text1(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $10, %rax
+  push %rcx
+  push %rbx
+  push %rsi
+  call b19
+  pop %rsi
+  pop %rbx
+  pop %rcx
+b35:
+  mov $1, %rdx
+  cmp %rdx, %rax
+  je b36
+b37:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text2(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b38:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text3(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $7, %rax
+  push %rbx
+  push %rsi
+  call b26
+  pop %rsi
+  pop %rbx
+b39:
+  mov $1, %rax
+  cmp %rax, %rcx
+  je b40
+b41:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text4(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b42:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text5(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rax
+  mov $1, %rcx
+  push %rbx
+  push %rsi
+  call b43
+  pop %rsi
+  pop %rbx
+b44:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text6(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rdx
+  cmp %rdx, %rcx
+  je b45
+b46:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text7(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b47:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text8(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text9(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rcx
+  mov $2, %rax
+  push %rbx
+  push %rsi
+  call b48
+  pop %rsi
+  pop %rbx
+b49:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text10(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rax, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text11(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rax
+  cmp %rax, %rcx
+  je b50
+b51:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text12(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b52:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text13(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rax
+  call b53
+b61:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text14(%rip), %rsi
+  mov $1, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov %rsi, %rdi
+  call write_int
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text15(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $1, %rax
+  cmp %rax, %rbx
+  je b62
+b63:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text16(%rip), %rsi
+  mov $5, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+b64:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text17(%rip), %rsi
+  mov $2, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  mov $0, %rax
+  mov %rax, %rdi
+  mov $60, %eax
+  syscall
+b62:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text18(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b64
+b53:
+  push %rbp
+  mov %rsp, %rbp
+  mov %rax, %rdx
+  mov $5, %rax
+  cmp %rdx, %rax
+  jg b54
+b55:
+  mov $0, %rax
+b56:
+  mov $0, %rcx
+  cmp %rcx, %rax
+  je b58
+b59:
+  mov %rsi, %rdx
+b60:
+  mov %rdx, %rsi
+  leave
+  ret
+b58:
+  mov $1, %rbx
+  jmp b60
+b54:
+  mov $1, %rax
+  add %rdx, %rax
+  movzbq %al, %rax
+  push %rdx
+  call b53
+  pop %rdx
+b57:
+  mov $1, %rax
+  jmp b56
+b50:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text19(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b52
+b48:
+  push %rbp
+  mov %rsp, %rbp
+  leave
+  ret
+b45:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text20(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b47
+b43:
+  push %rbp
+  mov %rsp, %rbp
+  leave
+  ret
+b40:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text21(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b42
+b26:
+  push %rbp
+  mov %rsp, %rbp
+  mov $1, %rdx
+  cmp %rax, %rdx
+  ja b27
+b28:
+  mov $0, %rsi
+b29:
+  mov $0, %rdx
+  cmp %rdx, %rsi
+  je b30
+b31:
+b32:
+  leave
+  ret
+b30:
+  mov $-1, %rdx
+  add %rdx, %rax
+  call b19
+b33:
+  mov %rax, %rcx
+  jmp b32
+b19:
+  push %rbp
+  mov %rsp, %rbp
+  mov $1, %rdx
+  cmp %rax, %rdx
+  ja b20
+b21:
+  mov $0, %rsi
+b22:
+  mov $0, %rdx
+  cmp %rdx, %rsi
+  je b23
+b24:
+b25:
+  mov %rbx, %rax
+  leave
+  ret
+b23:
+  mov $-1, %rdx
+  add %rdx, %rax
+  call b26
+b34:
+  mov %rcx, %rbx
+  jmp b25
+b20:
+  mov $1, %rbx
+  mov $1, %rsi
+  jmp b22
+b27:
+  mov $0, %rcx
+  mov $1, %rsi
+  jmp b29
+b36:
+  push %rax
+  push %rcx
+  push %rdx
+  push %rsi
+  push %rdi
+  push %r8
+  push %r11
+  mov $1, %eax
+  mov $1, %edi
+  lea text22(%rip), %rsi
+  mov $4, %edx
+  syscall
+  pop %r11
+  pop %r8
+  pop %rdi
+  pop %rsi
+  pop %rdx
+  pop %rcx
+  pop %rax
+  jmp b38
+b10:
+  push %rbp
+  mov %rsp, %rbp
+  mov %rdi, %rcx
+  mov $50, %rbx
+  cmp %rax, %rbx
+  jg b11
+b12:
+  mov $0, %rax
+b13:
+  mov $0, %rdx
+  cmp %rdx, %rax
+  je b15
+b16:
+  mov %r8, %rcx
+b17:
+  mov %rcx, %r8
+  leave
+  ret
+b15:
+  jmp b17
+b11:
+  mov %rcx, %rbx
+  add %rdx, %rbx
+  mov $1, %rsi
+  add %rsi, %rax
+  mov %rdx, %rdi
+  mov %rbx, %rdx
+  push %rcx
+  call b10
+  pop %rcx
+b14:
+  mov $1, %rax
+  jmp b13
+b1:
+  push %rbp
+  mov %rsp, %rbp
+  mov %rax, %rcx
+  mov $100, %rax
+  cmp %rcx, %rax
+  jg b2
+b3:
+  mov $0, %rax
+b4:
+  mov $0, %rcx
+  cmp %rcx, %rax
+  je b6
+b7:
+b8:
+  leave
+  ret
+b6:
+  mov $0, %rdx
+  jmp b8
+b2:
+  mov $1, %rax
+  add %rcx, %rax
+  push %rcx
+  call b1
+  pop %rcx
+b5:
+  add %rcx, %rdx
+  mov $1, %rax
+  jmp b4
+write_int:
+  mov %rdi, %rax
+  lea -1(%rsp), %rsi
+  mov $10, %rcx
+  xor %r8, %r8
+  test %rax, %rax
+  jns 1f
+  neg %rax
+  mov $1, %r8
+1:
+  xor %rdx, %rdx
+  div %rcx
+  add $48, %dl
+  mov %dl, (%rsi)
+  dec %rsi
+  test %rax, %rax
+  jnz 1b
+  test %r8, %r8
+  jz 2f
+  movb $45, (%rsi)
+  dec %rsi
+2:
+  inc %rsi
+  mov %rsp, %rdx
+  sub %rsi, %rdx
+  mov $1, %eax
+  mov $1, %edi
+  syscall
+  ret
+.section .rodata
+text0:
+  .ascii "\012"
+text1:
+  .ascii "\012"
+text2:
+  .ascii "false"
+text3:
+  .ascii "\012"
+text4:
+  .ascii "false"
+text5:
+  .ascii "\012"
+text6:
+  .ascii "("
+text7:
+  .ascii "false"
+text8:
+  .ascii ", "
+text9:
+  .ascii ")\012"
+text10:
+  .ascii "("
+text11:
+  .ascii ", "
+text12:
+  .ascii "false"
+text13:
+  .ascii ")\012"
+text14:
+  .ascii "("
+text15:
+  .ascii ", "
+text16:
+  .ascii "false"
+text17:
+  .ascii ")\012"
+text18:
+  .ascii "true"
+text19:
+  .ascii "true"
+text20:
+  .ascii "true"
+text21:
+  .ascii "true"
+text22:
+  .ascii "true"